@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Achievement progress belongs to the player rather than to a world, so it
+// lives in a profile shared by all save slots.
+const PROFILE_FILE = "profile.json"
+
+// eventKind is a kind of gameplay event achievements listen to.
+type eventKind int
+
+const (
+	EventDrive        eventKind = iota // Value: meters driven this frame
+	EventSpeed                         // Value: speed in m/s
+	EventCollision                     // the car hit something
+	EventChunkEntered                  // the car crossed into another chunk
+	EventRaceFinished                  // Value: race time in seconds
+	EventJobDone                       // Value: pay
+)
+
+// gameEvent is something that happened, and where: the chunk and road type under the car.
+type gameEvent struct {
+	Kind  eventKind
+	Value float32
+	Chunk int
+	Road  int
+}
+
+// achievementRule is how matching events move an achievement's progress.
+type achievementRule int
+
+const (
+	RuleCount    achievementRule = iota // one per event
+	RuleSum                             // adds the event's value
+	RuleMax                             // keeps the highest value
+	RuleDistinct                        // counts distinct chunk types
+	RuleStreak                          // adds the value, back to zero on a collision
+)
+
+// Matches any chunk or road type in an achievement's filters.
+const ANY = -1
+
+// achievementDef describes an achievement. It listens to one event kind,
+// optionally only in one chunk or road type, and unlocks when its progress reaches Goal.
+type achievementDef struct {
+	ID          string // key in the profile; never change once released
+	Name        string
+	Description string
+	Event       eventKind
+	Rule        achievementRule
+	Chunk, Road int
+	Goal        float32
+	// Formats progress for the achievements screen; plain numbers if nil.
+	Format func(float32) string
+}
+
+var achievementDefs = []achievementDef{
+	{ID: "ice_speed", Name: "Ice Rocket", Description: "Reach 180 km/h on ice",
+		Event: EventSpeed, Rule: RuleMax, Chunk: ANY, Road: RoadIce, Goal: 180 / 3.6, Format: formatSpeed},
+	{ID: "desert_10km", Name: "Dune Cruiser", Description: "Drive 10 km in the desert",
+		Event: EventDrive, Rule: RuleSum, Chunk: Desert, Road: ANY, Goal: 10000, Format: formatDistance},
+	{ID: "all_landscapes", Name: "Tourist", Description: "Visit all six kinds of landscape",
+		Event: EventChunkEntered, Rule: RuleDistinct, Chunk: ANY, Road: ANY, Goal: 6},
+	{ID: "clean_5km", Name: "Clean Record", Description: "Drive 5 km without a collision",
+		Event: EventDrive, Rule: RuleStreak, Chunk: ANY, Road: ANY, Goal: 5000, Format: formatDistance},
+	{ID: "first_race", Name: "Off the Line", Description: "Finish a checkpoint race",
+		Event: EventRaceFinished, Rule: RuleCount, Chunk: ANY, Road: ANY, Goal: 1},
+	{ID: "five_jobs", Name: "Working Wheels", Description: "Complete 5 delivery or taxi jobs",
+		Event: EventJobDone, Rule: RuleCount, Chunk: ANY, Road: ANY, Goal: 5},
+}
+
+// achievementProgress is the saved state of one achievement.
+type achievementProgress struct {
+	Value    float32   `json:"value"`
+	Seen     []int     `json:"seen,omitempty"` // chunk types, for RuleDistinct
+	Unlocked time.Time `json:"unlocked,omitempty"`
+}
+
+// profile is the on-disk player profile.
+type profile struct {
+	Achievements map[string]*achievementProgress `json:"achievements"`
+}
+
+var (
+	playerProfile = profile{Achievements: map[string]*achievementProgress{}}
+	// State to return to when the achievements screen is closed.
+	achievementsReturn GameState
+)
+
+// profilePath returns the profile file.
+func profilePath() string {
+	return filepath.Join(SAVE_DIR, PROFILE_FILE)
+}
+
+// loadProfile reads the profile; a missing file is a new player.
+func loadProfile() {
+	bytes, err := os.ReadFile(profilePath())
+	if err != nil {
+		return
+	}
+	var p profile
+	if err := json.Unmarshal(bytes, &p); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not read profile: %v", err)
+		return
+	}
+	if p.Achievements != nil {
+		playerProfile = p
+	}
+}
+
+// writeProfile stores the profile.
+func writeProfile() error {
+	bytes, err := json.MarshalIndent(playerProfile, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(SAVE_DIR, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(profilePath(), bytes, 0o644)
+}
+
+// progressOf returns the progress of an achievement, creating it on first use.
+func progressOf(def achievementDef) *achievementProgress {
+	p := playerProfile.Achievements[def.ID]
+	if p == nil {
+		p = &achievementProgress{}
+		playerProfile.Achievements[def.ID] = p
+	}
+	return p
+}
+
+// emitEvent reports a gameplay event at the car's position to every achievement.
+func emitEvent(kind eventKind, value float32) {
+	e := gameEvent{Kind: kind, Value: value, Chunk: ANY, Road: ANY}
+	if chunk := chunks[getChunkCoord(car.position)]; chunk != nil {
+		e.Chunk, e.Road = chunk.Type, chunk.RoadType
+	}
+	for _, def := range achievementDefs {
+		p := progressOf(def)
+		if !p.Unlocked.IsZero() {
+			continue
+		}
+		if def.Rule == RuleStreak && e.Kind == EventCollision {
+			p.Value = 0
+			continue
+		}
+		if e.Kind != def.Event || (def.Chunk != ANY && def.Chunk != e.Chunk) || (def.Road != ANY && def.Road != e.Road) {
+			continue
+		}
+		switch def.Rule {
+		case RuleCount:
+			p.Value++
+		case RuleSum, RuleStreak:
+			p.Value += e.Value
+		case RuleMax:
+			p.Value = float32(math.Max(float64(p.Value), float64(e.Value)))
+		case RuleDistinct:
+			seen := false
+			for _, t := range p.Seen {
+				seen = seen || t == e.Chunk
+			}
+			if !seen && e.Chunk != ANY {
+				p.Seen = append(p.Seen, e.Chunk)
+			}
+			p.Value = float32(len(p.Seen))
+		}
+		if p.Value >= def.Goal {
+			p.Unlocked = time.Now()
+			notify("Achievement unlocked: " + def.Name)
+			if err := writeProfile(); err != nil {
+				rl.TraceLog(rl.LogWarning, "could not save profile: %v", err)
+			}
+		}
+	}
+}
+
+// openAchievements shows the achievements screen.
+func openAchievements() {
+	achievementsReturn = currentState
+	currentState = Achievements
+}
+
+// achievementRect returns the bounds of the i-th achievement row.
+func achievementRect(i int) rl.Rectangle {
+	return uiRect(AnchorCenter, 0, float32(-180+62*i), 500, 56)
+}
+
+// achievementsBackRect returns the "Back" button of the achievements screen.
+func achievementsBackRect() rl.Rectangle {
+	return uiRect(AnchorCenter, 0, 230, 200, 40)
+}
+
+func updateAchievements() {
+	if uiClicked(achievementsBackRect()) || rl.IsKeyPressed(rl.KeyJ) {
+		currentState = achievementsReturn
+	}
+}
+
+// drawAchievements lists every achievement with a progress bar.
+func drawAchievements() {
+	renderer.ClearBackground(rl.RayWhite)
+	header := uiRect(AnchorTopLeft, 0, 30, 0, 30)
+	header.Width, _ = renderer.ScreenSize()
+	drawUITextCentered("Achievements", header, 0, 30, rl.Black)
+
+	for i, def := range achievementDefs {
+		p := progressOf(def)
+		rect := achievementRect(i)
+		color := rl.LightGray
+		if !p.Unlocked.IsZero() {
+			color = rl.Gold
+		}
+		renderer.DrawRectangle(rect, color)
+		drawUIText(def.Name, rect.X+ui(10), rect.Y+ui(5), 20, rl.Black)
+		drawUIText(def.Description, rect.X+ui(10), rect.Y+ui(30), 16, rl.DarkGray)
+
+		status := "Unlocked " + p.Unlocked.Format("2006-01-02")
+		if p.Unlocked.IsZero() {
+			format := func(v float32) string { return fmt.Sprintf("%.0f", v) }
+			if def.Format != nil {
+				format = def.Format
+			}
+			status = format(p.Value) + " / " + format(def.Goal)
+			bar := rl.Rectangle{X: rect.X + rect.Width - ui(170), Y: rect.Y + ui(36), Width: ui(160), Height: ui(10)}
+			renderer.DrawRectangle(bar, rl.Gray)
+			bar.Width *= rl.Clamp(p.Value/def.Goal, 0, 1)
+			renderer.DrawRectangle(bar, rl.DarkGreen)
+		}
+		width := float32(renderer.MeasureText(status, uiFont(16)))
+		drawUIText(status, rect.X+rect.Width-ui(10)-width, rect.Y+ui(8), 16, rl.Black)
+	}
+
+	drawUIButton(achievementsBackRect(), "Back", 20)
+}
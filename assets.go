@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Asset manifest read at start-up, relative to the working directory.
+const ASSET_MANIFEST = "assets/manifest.json"
+
+// assetEntry points at a model file that replaces a built-in primitive.
+type assetEntry struct {
+	Model string  `json:"model"` // glTF/GLB/OBJ path, relative to the manifest
+	Scale float32 `json:"scale"` // uniform scale; 0 means 1
+	Yaw   float32 `json:"yaw"`   // degrees to turn the model about Y
+	// Lift in meters, for models whose origin is not at the base.
+	OffsetY float32 `json:"offsetY"`
+}
+
+// assetManifest maps prop names (see propDefs), vehicle names and character
+// names to model files. Vehicles and characters face +Z in model space.
+type assetManifest struct {
+	Props      map[string]assetEntry `json:"props"`
+	Vehicles   map[string]assetEntry `json:"vehicles"`
+	Characters map[string]assetEntry `json:"characters"`
+}
+
+var manifest assetManifest
+
+// Set by initHeadless: models are then CPU-only stand-ins that carry a color
+// and no meshes, for drawing to a recordingRenderer without a GPU.
+var headless bool
+
+// headlessModel returns a CPU-only model of one color.
+func headlessModel(color rl.Color) rl.Model {
+	return rl.Model{MaterialCount: 1, Materials: &rl.Material{Maps: &rl.MaterialMap{Color: color}}, Transform: rl.MatrixIdentity()}
+}
+
+// loadAssetManifest reads the manifest. A missing manifest is not an error:
+// everything is then drawn with primitives.
+func loadAssetManifest() {
+	bytes, err := os.ReadFile(ASSET_MANIFEST)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			rl.TraceLog(rl.LogWarning, "asset manifest: %v", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(bytes, &manifest); err != nil {
+		rl.TraceLog(rl.LogWarning, "asset manifest: %v", err)
+	}
+}
+
+// loadAsset loads the model for a manifest entry together with the transform
+// that places it in the world. It reports false when the entry is empty or the
+// file is missing or unreadable, so the caller can fall back to a primitive.
+func loadAsset(entry assetEntry) (rl.Model, rl.Matrix, bool) {
+	if entry.Model == "" {
+		return rl.Model{}, rl.Matrix{}, false
+	}
+	path := filepath.Join(filepath.Dir(ASSET_MANIFEST), entry.Model)
+	if _, err := os.Stat(path); err != nil {
+		rl.TraceLog(rl.LogInfo, "asset %s not found, using primitive", path)
+		return rl.Model{}, rl.Matrix{}, false
+	}
+	// Textures referenced by the glTF or OBJ material are loaded with the model.
+	model := rl.LoadModel(path)
+	if model.MeshCount == 0 {
+		rl.TraceLog(rl.LogWarning, "asset %s has no meshes, using primitive", path)
+		return rl.Model{}, rl.Matrix{}, false
+	}
+	scale := entry.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	local := rl.MatrixMultiply(model.Transform, rl.MatrixScale(scale, scale, scale))
+	local = rl.MatrixMultiply(local, rl.MatrixRotateY(entry.Yaw*rl.Deg2rad))
+	local = rl.MatrixMultiply(local, rl.MatrixTranslate(0, entry.OffsetY, 0))
+	model.Transform = rl.MatrixIdentity()
+	return model, local, true
+}
+
+// loadModelOrPrimitive returns the entry's model with its placement baked
+// into model.Transform, or the given primitive when no asset is available.
+// The second result is false for the primitive, which is tinted when drawn.
+func loadModelOrPrimitive(entry assetEntry, primitive func() rl.Mesh) (rl.Model, bool) {
+	if headless {
+		return headlessModel(rl.White), false
+	}
+	if model, local, ok := loadAsset(entry); ok {
+		model.Transform = local
+		return model, true
+	}
+	return rl.LoadModelFromMesh(primitive()), false
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// CameraMode selects how the camera follows the car.
+type CameraMode int
+
+const (
+	CameraChase CameraMode = iota
+	CameraHood
+	CameraBumper
+	CameraTopDown
+	CameraOrbit
+)
+
+var cameraModeNames = []string{"Chase", "Hood", "Bumper", "Top-down", "Orbit"}
+
+// Field of view at rest and the extra degrees added at top speed.
+const (
+	CAMERA_BASE_FOV  float32 = 45
+	CAMERA_SPEED_FOV float32 = 20
+)
+
+var (
+	camera     rl.Camera3D
+	cameraMode CameraMode
+	// Jump straight to the desired pose on the next update instead of easing.
+	cameraSnap bool = true
+	// Orbit mode angles (radians) around the car and distance from it.
+	orbitYaw      float32 = math.Pi
+	orbitPitch    float32 = 0.4
+	orbitDistance float32 = 10
+	// Eased camera position before occlusion avoidance.
+	cameraIdeal rl.Vector3
+	// Current distance from the car allowed by obstacles; eases back out when clear.
+	cameraBoom float32
+)
+
+// Gap kept between the camera and an occluding obstacle.
+const CAMERA_OCCLUSION_MARGIN float32 = 0.5
+
+// resetCamera snaps the camera to its pose for the current mode.
+func resetCamera() {
+	cameraSnap = true
+	positionCamera(0)
+}
+
+// cycleCameraMode switches to the next camera mode.
+func cycleCameraMode() {
+	cameraMode = (cameraMode + 1) % CameraMode(len(cameraModeNames))
+	cameraSnap = true
+	notify("Camera: " + cameraModeNames[cameraMode])
+}
+
+// smoothFactor returns the lerp amount for exponential easing at the given rate.
+func smoothFactor(rate, dt float32) float32 {
+	return 1 - float32(math.Exp(float64(-rate*dt)))
+}
+
+// updateCamera handles the camera key and follows the car.
+func updateCamera() {
+	if rl.IsKeyPressed(rl.KeyC) {
+		cycleCameraMode()
+	}
+	positionCamera(rl.GetFrameTime())
+}
+
+// positionCamera moves the camera toward its pose for the current mode.
+func positionCamera(dt float32) {
+	forward := carForward()
+	flat := rl.Vector3Normalize(rl.Vector3{X: forward.X, Z: forward.Z})
+	up := rl.Vector3{Y: 1}
+	speed := float32(math.Abs(float64(car.speed)))
+	at := func(ahead, height float32) rl.Vector3 {
+		return rl.Vector3Add(car.position, rl.Vector3Add(rl.Vector3Scale(flat, ahead), rl.Vector3Scale(up, height)))
+	}
+
+	var position, target rl.Vector3
+	cameraUp := up
+	fov := CAMERA_BASE_FOV
+	ease := float32(0) // 0 means follow rigidly
+	avoidOcclusion := false
+	switch cameraMode {
+	case CameraChase:
+		// Pull back and up as speed builds so the road ahead stays in view.
+		position = at(-(6 + speed*0.08), 2.2+speed*0.02)
+		target = at(2, 0.5)
+		fov += CAMERA_SPEED_FOV * float32(math.Min(1, float64(speed/50)))
+		ease = 6
+		avoidOcclusion = true
+	case CameraHood:
+		position = at(0.3, 0.6)
+		target = at(10, 0.5)
+		fov += CAMERA_SPEED_FOV * float32(math.Min(1, float64(speed/50)))
+	case CameraBumper:
+		position = at(1.1, 0.2)
+		target = at(10, 0.2)
+		fov += CAMERA_SPEED_FOV * float32(math.Min(1, float64(speed/50)))
+	case CameraTopDown:
+		// Straight down with the car's heading at the top of the screen.
+		position = at(0, 40)
+		target = car.position
+		cameraUp = flat
+		ease = 4
+	case CameraOrbit:
+		// Right mouse drag rotates, wheel zooms.
+		if rl.IsMouseButtonDown(rl.MouseRightButton) {
+			delta := rl.GetMouseDelta()
+			orbitYaw += delta.X * 0.01
+			orbitPitch = rl.Clamp(orbitPitch+delta.Y*0.01, 0.05, 1.5)
+		}
+		orbitDistance = rl.Clamp(orbitDistance-rl.GetMouseWheelMove(), 3, 40)
+		cosPitch := float32(math.Cos(float64(orbitPitch)))
+		position = rl.Vector3Add(car.position, rl.Vector3{
+			X: float32(math.Cos(float64(orbitYaw))) * cosPitch * orbitDistance,
+			Y: float32(math.Sin(float64(orbitPitch))) * orbitDistance,
+			Z: float32(math.Sin(float64(orbitYaw))) * cosPitch * orbitDistance,
+		})
+		target = car.position
+		avoidOcclusion = true
+	}
+
+	snap := cameraSnap
+	if cameraSnap || ease == 0 {
+		cameraIdeal = position
+		camera.Target = target
+		camera.Up = cameraUp
+		camera.Fovy = fov
+		cameraSnap = false
+	} else {
+		t := smoothFactor(ease, dt)
+		cameraIdeal = rl.Vector3Lerp(cameraIdeal, position, t)
+		// The target follows faster so the car stays centered.
+		camera.Target = rl.Vector3Lerp(camera.Target, target, smoothFactor(ease*2, dt))
+		camera.Up = rl.Vector3Normalize(rl.Vector3Lerp(camera.Up, cameraUp, t))
+		camera.Fovy += (fov - camera.Fovy) * smoothFactor(3, dt)
+	}
+	camera.Position = cameraIdeal
+	if avoidOcclusion {
+		camera.Position = unoccludedCameraPosition(cameraIdeal, snap, dt)
+	}
+	camera.Projection = rl.CameraPerspective
+}
+
+// unoccludedCameraPosition casts a ray from the car to the desired camera
+// position and pulls the camera in front of the nearest collider it hits.
+// The camera jumps in immediately but eases back out once the view clears.
+func unoccludedCameraPosition(desired rl.Vector3, snap bool, dt float32) rl.Vector3 {
+	pivot := rl.Vector3Add(car.position, rl.Vector3{Y: 1})
+	offset := rl.Vector3Subtract(desired, pivot)
+	length := rl.Vector3Length(offset)
+	if length < 0.001 {
+		return desired
+	}
+	ray := rl.Ray{Position: pivot, Direction: rl.Vector3Scale(offset, 1/length)}
+
+	allowed := length
+	for _, box := range nearbyColliders(car.position, 1) {
+		// Skip colliders that cannot be between the car and the camera.
+		centerX := (box.Min.X + box.Max.X) / 2
+		centerZ := (box.Min.Z + box.Max.Z) / 2
+		reach := length + (box.Max.X - box.Min.X) + (box.Max.Z - box.Min.Z)
+		if float32(math.Abs(float64(centerX-pivot.X))) > reach || float32(math.Abs(float64(centerZ-pivot.Z))) > reach {
+			continue
+		}
+		hit := rl.GetRayCollisionBox(ray, box)
+		if hit.Hit && hit.Distance < allowed+CAMERA_OCCLUSION_MARGIN {
+			allowed = float32(math.Max(0.5, float64(hit.Distance-CAMERA_OCCLUSION_MARGIN)))
+		}
+	}
+
+	if snap || allowed < cameraBoom {
+		cameraBoom = allowed
+	} else {
+		cameraBoom += (allowed - cameraBoom) * smoothFactor(2, dt)
+	}
+	return rl.Vector3Add(pivot, rl.Vector3Scale(ray.Direction, cameraBoom))
+}
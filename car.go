@@ -6,6 +6,12 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// Fraction of a full tank burned per meter driven (a tank lasts 100 km).
+const FUEL_PER_METER float32 = 1.0 / 100000
+
+// Top speed in m/s once the tank is empty.
+const EMPTY_TANK_MAX_SPEED float32 = 5.0
+
 type Car struct {
 	position rl.Vector3
 	yaw      float32
@@ -14,12 +20,28 @@ type Car struct {
 	steering float32
 	velocity rl.Vector3
 	grounded bool
-	model    rl.Model
+	// Touching an obstacle this frame, so a crash is only counted once.
+	colliding bool
+	damage    float32 // 0 (intact) to 100 (wrecked)
+	fuel      float32 // fraction of a full tank
+	model     rl.Model
+	// Tint for the primitive fallback; asset models keep their own colors.
+	tint rl.Color
 }
 
 var car Car
 
 func initCar() {
+	// Reuse the model when restarting so it isn't loaded again.
+	model, tint := car.model, car.tint
+	if model.MeshCount == 0 {
+		var asset bool
+		model, asset = loadModelOrPrimitive(manifest.Vehicles["player"], func() rl.Mesh { return rl.GenMeshCube(1, 0.5, 2) })
+		tint = rl.Red
+		if asset {
+			tint = rl.White
+		}
+	}
 	// Spawn on road: center of chunk (0,0) is at (CHUNK_SIZE/2, 0, CHUNK_SIZE/2)
 	car = Car{
 		position: rl.Vector3{X: CHUNK_SIZE / 2, Y: 0, Z: CHUNK_SIZE / 2},
@@ -28,7 +50,9 @@ func initCar() {
 		speed:    0,
 		steering: 0,
 		grounded: true,
-		model:    rl.LoadModelFromMesh(rl.GenMeshCube(1, 0.5, 2)),
+		fuel:     1,
+		model:    model,
+		tint:     tint,
 	}
 }
 
@@ -55,9 +79,16 @@ func updateCar() {
 		maxSpeed = 38.9 // Default road speed if no chunk data
 	}
 
+	// A damaged car loses up to half its top speed; an empty tank leaves it limping.
+	maxSpeed *= 1 - car.damage/200
+	if car.fuel <= 0 && maxSpeed > EMPTY_TANK_MAX_SPEED {
+		maxSpeed = EMPTY_TANK_MAX_SPEED
+	}
+
 	// Base acceleration
 	baseAccel := float32(5.0) // m/s^2
-	accel := baseAccel * terrainMultiplier
+	grip := weatherGrip()
+	accel := baseAccel * terrainMultiplier * grip
 
 	// Define a deceleration factor for overspeed.
 	overspeedDecelFactor := float32(2.0) // Stronger deceleration
@@ -99,12 +130,12 @@ func updateCar() {
 
 	// Steering
 	if rl.IsKeyDown(rl.KeyLeft) {
-		car.steering -= 2 * dt
+		car.steering -= 2 * grip * dt
 		if car.steering < -1 {
 			car.steering = -1
 		}
 	} else if rl.IsKeyDown(rl.KeyRight) {
-		car.steering += 2 * dt
+		car.steering += 2 * grip * dt
 		if car.steering > 1 {
 			car.steering = 1
 		}
@@ -115,20 +146,37 @@ func updateCar() {
 	car.yaw += car.steering * dt
 
 	// Compute forward direction
-	forward := rl.Vector3{
-		X: float32(math.Cos(float64(car.yaw))) * float32(math.Cos(float64(car.pitch))),
-		Y: float32(math.Sin(float64(car.pitch))),
-		Z: float32(math.Sin(float64(car.yaw))) * float32(math.Cos(float64(car.pitch))),
-	}
+	forward := carForward()
 
 	// Update position
 	car.position.X += forward.X * car.speed * dt
 	car.position.Z += forward.Z * car.speed * dt
 
-	// Collision check
-	if checkCollisions(car.position) {
+	// Collision check; light props are knocked over before they can block the car.
+	knockProps(car.position)
+	if checkCollisions(oldPos, car.position) {
 		car.position = oldPos
+		if !car.colliding {
+			recordCollision()
+			emitCollisionSparks(float32(math.Abs(float64(car.speed))))
+			reportCrash(float32(math.Abs(float64(car.speed))))
+			emitEvent(EventCollision, 0)
+			car.damage = float32(math.Min(100, float64(car.damage+float32(math.Abs(float64(car.speed))))))
+		}
+		car.colliding = true
 		car.speed = 0
+	} else {
+		car.colliding = false
+	}
+
+	// Fuel and trip computer
+	moved := rl.Vector3Distance(oldPos, car.position)
+	car.fuel = float32(math.Max(0, float64(car.fuel-moved*FUEL_PER_METER)))
+	updateTrip(moved, dt)
+	emitEvent(EventDrive, moved)
+	emitEvent(EventSpeed, float32(math.Abs(float64(car.speed))))
+	if getChunkCoord(oldPos) != getChunkCoord(car.position) {
+		emitEvent(EventChunkEntered, 1)
 	}
 
 	// Grounded check
@@ -137,12 +185,21 @@ func updateCar() {
 	}
 }
 
+// carForward returns the unit vector the car is facing.
+func carForward() rl.Vector3 {
+	return rl.Vector3{
+		X: float32(math.Cos(float64(car.yaw))) * float32(math.Cos(float64(car.pitch))),
+		Y: float32(math.Sin(float64(car.pitch))),
+		Z: float32(math.Sin(float64(car.yaw))) * float32(math.Cos(float64(car.pitch))),
+	}
+}
+
 func drawCar() {
 	trans := rl.MatrixTranslate(car.position.X, car.position.Y, car.position.Z)
-	rotY := rl.MatrixRotateY(car.yaw)
-	rotX := rl.MatrixRotateX(car.pitch)
+	// The model faces +Z; turn it to face carForward.
+	rotY := rl.MatrixRotateY(math.Pi/2 - car.yaw)
+	rotX := rl.MatrixRotateX(-car.pitch)
 	transform := rl.MatrixMultiply(rotX, rotY)
 	transform = rl.MatrixMultiply(transform, trans)
-	car.model.Transform = transform
-	rl.DrawModel(car.model, rl.Vector3{}, 1, rl.Red)
+	renderer.DrawModel(car.model, []rl.Matrix{transform}, car.tint)
 }
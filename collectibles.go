@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Coins come in rows along a main road; a chunk has a row with COIN_ROW_CHANCE.
+const (
+	COIN_ROW_CHANCE float32 = 0.6
+	COINS_PER_ROW           = 5
+	COIN_SPACING    float32 = 4
+)
+
+// Chance that a City or Forest chunk hides a token.
+const TOKEN_CHANCE float32 = 0.1
+
+// Points per coin and per token.
+const (
+	COIN_SCORE  = 1
+	TOKEN_SCORE = 25
+)
+
+// Collectibles are picked up within this distance of the car.
+const PICKUP_RADIUS float32 = 1.8
+
+// collectibleKind is what a collectible is worth.
+type collectibleKind int
+
+const (
+	CollectCoin collectibleKind = iota
+	CollectToken
+)
+
+// collectible is a pickup in a chunk. Index identifies it within the chunk:
+// coins are numbered along their row and the token comes after them.
+type collectible struct {
+	Index    int
+	Kind     collectibleKind
+	Position rl.Vector3
+}
+
+// collectTotal counts what has been picked up in one chunk type.
+type collectTotal struct {
+	Coins  int `json:"coins"`
+	Tokens int `json:"tokens"`
+}
+
+var (
+	// Indices of the collectibles picked up in each chunk, across sessions.
+	collected = map[Coord]map[int]bool{}
+	// Pickups by chunk type, across sessions.
+	collectTotals [6]collectTotal
+)
+
+// chunkCollectibles places the collectibles of a newly built chunk. Picked
+// up ones are kept too; collected says which.
+func chunkCollectibles(chunk *Chunk) []collectible {
+	if chunk.Coord == (Coord{0, 0}) {
+		return nil
+	}
+	r := seededRandom("collect", chunk.Coord.X, chunk.Coord.Y)
+	center := chunkCenter(chunk.Coord)
+
+	var items []collectible
+	if r.Float32() < COIN_ROW_CHANCE {
+		// Down the middle of a lane, starting clear of the intersection.
+		dir := r.Intn(4)
+		lane := rl.Vector3Scale(trafficDirs[(dir+1)%4], LANE_OFFSET*float32(2*r.Intn(2)-1))
+		for k := 0; k < COINS_PER_ROW; k++ {
+			position := rl.Vector3Add(center, rl.Vector3Scale(trafficDirs[dir], 8+float32(k)*COIN_SPACING))
+			items = append(items, collectible{Index: k, Kind: CollectCoin, Position: rl.Vector3Add(position, lane)})
+		}
+	}
+
+	if (chunk.Type != City && chunk.Type != Forest) || r.Float32() >= TOKEN_CHANCE {
+		return items
+	}
+	var spot rl.Vector3
+	found := false
+	switch chunk.Type {
+	case City:
+		// Behind a building, on the side away from the nearest main road.
+		var buildings []propInstance
+		for _, prop := range chunk.Props {
+			if prop.Type == PropBuilding {
+				buildings = append(buildings, prop)
+			}
+		}
+		if len(buildings) == 0 {
+			break
+		}
+		building := buildings[r.Intn(len(buildings))]
+		spot = building.Position
+		behind := propDefs[PropBuilding].Size.X/2 + 2
+		if dx, dz := spot.X-center.X, spot.Z-center.Z; math.Abs(float64(dx)) < math.Abs(float64(dz)) {
+			spot.X += float32(math.Copysign(float64(behind), float64(dx)))
+		} else {
+			spot.Z += float32(math.Copysign(float64(behind), float64(dz)))
+		}
+		found = !insideChunkCollider(chunk, spot)
+	case Forest:
+		// Off the road among the trees.
+		origin := rl.Vector3{X: float32(chunk.Coord.X) * CHUNK_SIZE, Z: float32(chunk.Coord.Y) * CHUNK_SIZE}
+		for try := 0; try < 5 && !found; try++ {
+			x, z := r.Float32()*CHUNK_SIZE, r.Float32()*CHUNK_SIZE
+			spot = rl.Vector3{X: origin.X + x, Z: origin.Z + z}
+			found = !isPositionOnRoad(x, z) && !insideChunkCollider(chunk, spot)
+		}
+	}
+	if found {
+		items = append(items, collectible{Index: COINS_PER_ROW, Kind: CollectToken, Position: spot})
+	}
+	return items
+}
+
+// insideChunkCollider reports whether pos is inside one of the chunk's own
+// colliders; unlike insideCollider it doesn't depend on which neighbors are loaded.
+func insideChunkCollider(chunk *Chunk, pos rl.Vector3) bool {
+	for _, box := range chunk.Colliders {
+		if pos.X > box.Min.X && pos.X < box.Max.X && pos.Z > box.Min.Z && pos.Z < box.Max.Z {
+			return true
+		}
+	}
+	return false
+}
+
+// score adds up every collectible picked up.
+func score() int {
+	total := 0
+	for _, t := range collectTotals {
+		total += t.Coins*COIN_SCORE + t.Tokens*TOKEN_SCORE
+	}
+	return total
+}
+
+// updateCollectibles picks up whatever the car drives through.
+func updateCollectibles() {
+	chunk := chunks[getChunkCoord(car.position)]
+	if chunk == nil {
+		return
+	}
+	for _, item := range chunk.Collectibles {
+		if collected[chunk.Coord][item.Index] {
+			continue
+		}
+		dx, dz := item.Position.X-car.position.X, item.Position.Z-car.position.Z
+		if dx*dx+dz*dz > PICKUP_RADIUS*PICKUP_RADIUS {
+			continue
+		}
+		if collected[chunk.Coord] == nil {
+			collected[chunk.Coord] = map[int]bool{}
+		}
+		collected[chunk.Coord][item.Index] = true
+		if item.Kind == CollectToken {
+			collectTotals[chunk.Type].Tokens++
+			notify(fmt.Sprintf("Token found! %d in %s", collectTotals[chunk.Type].Tokens, typeNames[chunk.Type]))
+		} else {
+			collectTotals[chunk.Type].Coins++
+		}
+	}
+}
+
+// drawCollectibles draws the remaining coins and tokens near the car, bobbing.
+func drawCollectibles() {
+	t := float32(rl.GetTime())
+	center := getChunkCoord(car.position)
+	for i := center.X - 2; i <= center.X+2; i++ {
+		for j := center.Y - 2; j <= center.Y+2; j++ {
+			chunk := chunks[Coord{i, j}]
+			if chunk == nil {
+				continue
+			}
+			for _, item := range chunk.Collectibles {
+				if collected[chunk.Coord][item.Index] {
+					continue
+				}
+				position := item.Position
+				position.Y = 1 + 0.2*float32(math.Sin(float64(t*3+float32(item.Index))))
+				if item.Kind == CollectToken {
+					renderer.DrawSphere(position, 0.5, rl.Magenta)
+				} else {
+					renderer.DrawCube(position, rl.Vector3{X: 0.7, Y: 0.7, Z: 0.7}, rl.Gold)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Must match MAX_LIGHTS in the instancing shader.
+const MAX_LIGHTS = 16
+
+var (
+	// Hour of the day, 0 to 24.
+	timeOfDay float32 = 10
+	// Real seconds per in-game day; 0 stops the clock at the current time.
+	dayLengthOptions = []float32{120, 600, 1800, 0}
+	dayLengthIndex   = 1
+
+	// Streetlights relative to the center of a City chunk: the intersection
+	// corners and one beside each arm of the "+" road, just behind the
+	// sidewalks so pedestrians don't walk through them.
+	streetlightOffsets = []rl.Vector2{
+		{X: 4.5, Y: 4.5}, {X: -4.5, Y: 4.5}, {X: 4.5, Y: -4.5}, {X: -4.5, Y: -4.5},
+		{X: 15, Y: 4.5}, {X: -15, Y: -4.5}, {X: 4.5, Y: -15}, {X: -4.5, Y: 15},
+	}
+
+	// Shader uniform locations, looked up by initLighting.
+	sunDirLoc, sunColorLoc, ambientLoc        int32
+	lightPosLoc, lightColorLoc, lightCountLoc int32
+	headPosLoc, headDirLoc, headOnLoc         int32
+	viewPosLoc, fogColorLoc, fogDensityLoc    int32
+	fogStartLoc, fogEndLoc                    int32
+)
+
+// Sky colors blended by sun height.
+var (
+	skyNight = rl.NewColor(10, 12, 35, 255)
+	skyDusk  = rl.NewColor(250, 150, 90, 255)
+	skyDay   = rl.SkyBlue
+)
+
+// initLighting looks up the lighting and fog uniforms of the instancing shader.
+func initLighting() {
+	if !instancingSupported {
+		return
+	}
+	loc := func(name string) int32 { return rl.GetShaderLocation(instancingShader, name) }
+	sunDirLoc, sunColorLoc, ambientLoc = loc("sunDir"), loc("sunColor"), loc("ambient")
+	lightPosLoc, lightColorLoc, lightCountLoc = loc("lightPos"), loc("lightColor"), loc("lightCount")
+	headPosLoc, headDirLoc, headOnLoc = loc("headPos"), loc("headDir"), loc("headOn")
+	viewPosLoc, fogColorLoc, fogDensityLoc = loc("viewPos"), loc("fogColor"), loc("fogDensity")
+	fogStartLoc, fogEndLoc = loc("fogStart"), loc("fogEnd")
+}
+
+// updateDayNight advances the clock.
+func updateDayNight() {
+	length := dayLengthOptions[dayLengthIndex]
+	if length <= 0 {
+		return
+	}
+	timeOfDay += 24 * rl.GetFrameTime() / length
+	timeOfDay = float32(math.Mod(float64(timeOfDay), 24))
+}
+
+// dayLengthLabel describes the day length setting.
+func dayLengthLabel() string {
+	length := dayLengthOptions[dayLengthIndex]
+	if length <= 0 {
+		return "Day Cycle: Off"
+	}
+	return fmt.Sprintf("Day Cycle: %.0f min", length/60)
+}
+
+// cycleDayLength selects the next day length.
+func cycleDayLength() {
+	dayLengthIndex = (dayLengthIndex + 1) % len(dayLengthOptions)
+}
+
+// timeOfDayLabel shows the clock as hh:mm.
+func timeOfDayLabel() string {
+	return fmt.Sprintf("Time: %02d:%02d", int(timeOfDay), int(timeOfDay*60)%60)
+}
+
+// advanceTimeOfDay jumps the clock forward three hours.
+func advanceTimeOfDay() {
+	timeOfDay = float32(math.Mod(float64(timeOfDay)+3, 24))
+}
+
+// sunAngle returns the sun's angle above the eastern horizon: 0 at 06:00, π/2 at noon.
+func sunAngle() float32 {
+	return (timeOfDay - 6) / 12 * math.Pi
+}
+
+// sunHeight is the sine of the sun's elevation; negative at night.
+func sunHeight() float32 {
+	return float32(math.Sin(float64(sunAngle())))
+}
+
+// sunDirection returns the direction sunlight travels, from the sun toward the ground.
+func sunDirection() rl.Vector3 {
+	angle := float64(sunAngle())
+	return rl.Vector3Normalize(rl.Vector3{X: -float32(math.Cos(angle)), Y: -float32(math.Sin(angle)), Z: -0.3})
+}
+
+// isNight reports whether lights should be on.
+func isNight() bool {
+	return sunHeight() < 0.1
+}
+
+// daylight returns how bright the sun is, from 0 at night to 1 during the day.
+func daylight() float32 {
+	return rl.Clamp((sunHeight()+0.1)/0.4, 0, 1)
+}
+
+// skyColor returns the clear color for the current time of day.
+func skyColor() rl.Color {
+	h := sunHeight()
+	if h < 0 {
+		return rl.ColorLerp(skyNight, skyDusk, rl.Clamp((h+0.2)/0.2, 0, 1))
+	}
+	return rl.ColorLerp(skyDusk, skyDay, rl.Clamp(h/0.3, 0, 1))
+}
+
+// nearbyStreetlights returns the lamp positions of the streetlights closest to the car.
+func nearbyStreetlights() []rl.Vector3 {
+	var lamps []rl.Vector3
+	playerChunk := getChunkCoord(car.position)
+	for i := playerChunk.X - 2; i <= playerChunk.X+2; i++ {
+		for j := playerChunk.Y - 2; j <= playerChunk.Y+2; j++ {
+			chunk := chunks[Coord{i, j}]
+			if chunk == nil {
+				continue
+			}
+			for _, prop := range chunk.Props {
+				if prop.Type == PropStreetlight {
+					lamps = append(lamps, rl.Vector3{X: prop.Position.X, Y: propDefs[PropStreetlight].Size.Y, Z: prop.Position.Z})
+				}
+			}
+		}
+	}
+	sort.Slice(lamps, func(a, b int) bool {
+		return rl.Vector3Distance(lamps[a], car.position) < rl.Vector3Distance(lamps[b], car.position)
+	})
+	if len(lamps) > MAX_LIGHTS {
+		lamps = lamps[:MAX_LIGHTS]
+	}
+	return lamps
+}
+
+// applyLighting uploads the sun, streetlights and headlights to the shader.
+func applyLighting() {
+	if !instancingSupported {
+		return
+	}
+	dir := sunDirection()
+	sunDir := []float32{dir.X, dir.Y, dir.Z}
+	light := daylight()
+	sunColor := []float32{light, light * 0.95, light * 0.85}
+	ambient := []float32{0.15 + 0.35*light, 0.15 + 0.35*light, 0.25 + 0.3*light}
+	rl.SetShaderValue(instancingShader, sunDirLoc, sunDir, rl.ShaderUniformVec3)
+	rl.SetShaderValue(instancingShader, sunColorLoc, sunColor, rl.ShaderUniformVec3)
+	rl.SetShaderValue(instancingShader, ambientLoc, ambient, rl.ShaderUniformVec3)
+
+	var positions, colors []float32
+	if isNight() {
+		for _, lamp := range nearbyStreetlights() {
+			positions = append(positions, lamp.X, lamp.Y, lamp.Z)
+			colors = append(colors, 1.0, 0.85, 0.5)
+		}
+	}
+	count := int32(len(positions) / 3)
+	if count > 0 {
+		rl.SetShaderValueV(instancingShader, lightPosLoc, positions, rl.ShaderUniformVec3, count)
+		rl.SetShaderValueV(instancingShader, lightColorLoc, colors, rl.ShaderUniformVec3, count)
+	}
+	rl.SetShaderValue(instancingShader, lightCountLoc, []float32{float32(count)}, rl.ShaderUniformFloat)
+
+	headOn := float32(0)
+	if isNight() {
+		headOn = 1
+	}
+	forward := carForward()
+	headPos := rl.Vector3Add(car.position, rl.Vector3Scale(forward, 1))
+	rl.SetShaderValue(instancingShader, headPosLoc, []float32{headPos.X, headPos.Y + 0.3, headPos.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(instancingShader, headDirLoc, []float32{forward.X, forward.Y - 0.1, forward.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(instancingShader, headOnLoc, []float32{headOn}, rl.ShaderUniformFloat)
+}
+
+// drawLamps draws the glowing lamp heads and headlights at night.
+func drawLamps() {
+	if !isNight() {
+		return
+	}
+	for _, lamp := range nearbyStreetlights() {
+		renderer.DrawSphere(lamp, 0.4, rl.Yellow)
+	}
+	forward := carForward()
+	right := rl.Vector3{X: -forward.Z, Z: forward.X}
+	front := rl.Vector3Add(car.position, rl.Vector3Scale(forward, 1))
+	for _, side := range []float32{-0.35, 0.35} {
+		renderer.DrawSphere(rl.Vector3Add(front, rl.Vector3Scale(right, side)), 0.12, rl.RayWhite)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -11,6 +12,10 @@ type GameState int
 const (
 	Menu GameState = iota
 	Playing
+	WorldMap
+	SlotScreen
+	PhotoMode
+	Achievements
 )
 
 var currentState GameState
@@ -21,149 +26,360 @@ var showSettingsOverlay bool = false
 // Toggle for displaying the FPS counter.
 var showFPSCounter bool = true
 
-// Toggle for displaying the car's speed in km/h.
+// Toggle for displaying the car's speed.
 var showSpeedKmh bool = false
 
+// settingsButton is one row of the settings overlay.
+type settingsButton struct {
+	label  func() string
+	action func()
+}
+
+// Buttons shown in the settings overlay, top to bottom.
+var settingsButtons = []settingsButton{
+	{
+		label:  func() string { return "FPS: " + onOff(showFPSCounter) },
+		action: func() { showFPSCounter = !showFPSCounter },
+	},
+	{
+		label:  func() string { return "Speed: " + onOff(showSpeedKmh) },
+		action: func() { showSpeedKmh = !showSpeedKmh },
+	},
+	{
+		label: func() string {
+			if useMph {
+				return "Units: mph"
+			}
+			return "Units: km/h"
+		},
+		action: func() { useMph = !useMph },
+	},
+	{
+		label: func() string {
+			if minimapNorthUp {
+				return "Minimap: North Up"
+			}
+			return "Minimap: Heading Up"
+		},
+		action: func() { minimapNorthUp = !minimapNorthUp },
+	},
+	{
+		label:  uiScaleLabel,
+		action: cycleUIScale,
+	},
+	{
+		label:  dayLengthLabel,
+		action: cycleDayLength,
+	},
+	{
+		label:  timeOfDayLabel,
+		action: advanceTimeOfDay,
+	},
+	{
+		label:  func() string { return "Save Game" },
+		action: func() { openSlotScreen(true) },
+	},
+	{
+		label: func() string { return "Return to Main Menu" },
+		action: func() {
+			currentState = Menu
+			showSettingsOverlay = false
+			if err := writeSave(AUTOSAVE_SLOT); err != nil {
+				rl.TraceLog(rl.LogWarning, "could not save game: %v", err)
+			}
+		},
+	},
+}
+
+// onOff formats a toggle for a button label.
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// menuButton is a main menu entry; enabled reports whether it can be used.
+type menuButton struct {
+	label   string
+	enabled func() bool
+	action  func()
+}
+
+// Main menu entries, top to bottom.
+var menuButtons = []menuButton{
+	{
+		label:   "Continue",
+		enabled: func() bool { return sessionActive || continueSavePath() != "" },
+		action:  continueGame,
+	},
+	{
+		label:   "New Game",
+		enabled: func() bool { return true },
+		action:  newGame,
+	},
+	{
+		label:   "Load Game",
+		enabled: func() bool { return true },
+		action:  func() { openSlotScreen(false) },
+	},
+	{
+		label:   "Achievements",
+		enabled: func() bool { return true },
+		action:  openAchievements,
+	},
+}
+
+// menuButtonRect returns the i-th main menu button.
+func menuButtonRect(i int) rl.Rectangle {
+	return uiRect(AnchorCenter, 0, float32(-65+65*i), 200, 50)
+}
+
+// gearIconRect returns the settings gear icon in the top left.
+func gearIconRect() rl.Rectangle {
+	return uiRect(AnchorTopLeft, 10, 10, 40, 40)
+}
+
+// settingsPanelRect returns the centered settings panel, sized to fit its buttons.
+func settingsPanelRect() rl.Rectangle {
+	return uiRect(AnchorCenter, 0, 0, 300, float32(90+50*len(settingsButtons)))
+}
+
+// settingsButtonRect returns the bounds of the i-th settings button.
+func settingsButtonRect(i int) rl.Rectangle {
+	panel := settingsPanelRect()
+	return rl.Rectangle{X: panel.X + ui(50), Y: panel.Y + ui(float32(70+50*i)), Width: ui(200), Height: ui(40)}
+}
+
 func initGame() {
 	currentState = Menu
-	initCar()
-	initWorld()
+	initProps()
+	initLighting()
+	initSky()
+	initTraffic()
+	initPedestrians()
+	loadProfile()
 	// Ensure settings overlay is off when starting
 	showSettingsOverlay = false
 }
 
+// initHeadless sets the game up like initGame but without a window or GPU:
+// drawing goes to r and models are CPU-only. Lighting, the sky and the
+// profile are left out, so only the world and the HUD can be drawn.
+func initHeadless(r *recordingRenderer) {
+	headless = true
+	renderer = r
+	currentState = Menu
+	initPropsHeadless()
+	initTraffic()
+	initPedestrians()
+	updateUIScale()
+	showSettingsOverlay = false
+}
+
+// startSession enters play with the world and car already set up.
+func startSession() {
+	sessionActive = true
+	autosaveTimer = 0
+	resetCamera()
+	raceState = RaceOff
+	activeMission, missionsTaken = nil, map[Coord]bool{}
+	clearPursuit()
+	fallingProps = nil
+	emitEvent(EventChunkEntered, 1)
+	showSettingsOverlay = false
+	currentState = Playing
+}
+
+// newGame starts a fresh world with a new seed.
+func newGame() {
+	worldSeed = time.Now().UnixNano()
+	discovered = map[Coord]discoveredChunk{}
+	odometer = 0
+	trips = [2]TripStats{}
+	cash = 0
+	collected, collectTotals = map[Coord]map[int]bool{}, [6]collectTotal{}
+	playTime = 0
+	initCar()
+	initWorld()
+	startSession()
+}
+
+// continueGame resumes the running session or the most recent autosave.
+func continueGame() {
+	if sessionActive {
+		currentState = Playing
+		return
+	}
+	if err := loadSave(continueSavePath()); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not load save: %v", err)
+	}
+}
+
 func updateGame() {
+	updateUIScale()
 	switch currentState {
 	case Menu:
-		// Main menu: Only a "Play" button.
-		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			mousePos := rl.GetMousePosition()
-			screenW, screenH := rl.GetScreenWidth(), rl.GetScreenHeight()
-			playX, playY := float32(screenW/2-100), float32(screenH/2-25)
-			if mousePos.X >= playX && mousePos.X <= playX+200 &&
-				mousePos.Y >= playY && mousePos.Y <= playY+50 {
-				currentState = Playing
+		for i, button := range menuButtons {
+			if button.enabled() && uiClicked(menuButtonRect(i)) {
+				button.action()
+				break
 			}
 		}
 	case Playing:
+		updateAutosave()
 		// In-game, update settings overlay UI:
-		mousePos := rl.GetMousePosition()
-		// Gear icon area (top left 40x40)
-		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			if mousePos.X >= 10 && mousePos.X <= 50 &&
-				mousePos.Y >= 10 && mousePos.Y <= 50 {
-				// Toggle settings overlay.
-				showSettingsOverlay = !showSettingsOverlay
-			}
+		if uiClicked(gearIconRect()) {
+			// Toggle settings overlay; capture a save thumbnail before it is drawn.
+			showSettingsOverlay = !showSettingsOverlay
+			thumbnailWanted = showSettingsOverlay
 		}
 
 		// If settings overlay is open, check its buttons.
 		if showSettingsOverlay {
-			screenW, screenH := rl.GetScreenWidth(), rl.GetScreenHeight()
-			// Panel (centered, 300x250)
-			panelX, panelY := float32(screenW/2-150), float32(screenH/2-125)
-			// Button positions relative to panel.
-			toggleFPSX, toggleFPSY := panelX+50, panelY+70
-			toggleSpeedX, toggleSpeedY := panelX+50, panelY+130
-			returnX, returnY := panelX+50, panelY+190
-
-			if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-				// Toggle FPS button.
-				if mousePos.X >= toggleFPSX && mousePos.X <= toggleFPSX+200 &&
-					mousePos.Y >= toggleFPSY && mousePos.Y <= toggleFPSY+40 {
-					showFPSCounter = !showFPSCounter
-				}
-				// Toggle Speed button.
-				if mousePos.X >= toggleSpeedX && mousePos.X <= toggleSpeedX+200 &&
-					mousePos.Y >= toggleSpeedY && mousePos.Y <= toggleSpeedY+40 {
-					showSpeedKmh = !showSpeedKmh
-				}
-				// Return to Main Menu button.
-				if mousePos.X >= returnX && mousePos.X <= returnX+200 &&
-					mousePos.Y >= returnY && mousePos.Y <= returnY+40 {
-					currentState = Menu
-					showSettingsOverlay = false
+			for i, button := range settingsButtons {
+				if uiClicked(settingsButtonRect(i)) {
+					button.action()
+					break
 				}
 			}
 		} else {
-			updateCar()
+			updateRace()
+			updateGhosts()
+			updateMissions()
+			updatePolice()
+			updateCollectibles()
+			updateLandmarks()
+			if !raceHoldingCar() {
+				updateCar()
+			}
+			updateKnockedProps()
+			updateTraffic()
+			updatePedestrians()
+			updateCamera()
+			updateDayNight()
+			updateWeather()
+			updateSky()
+			updateParticles()
+			updateDiscovery()
+			updateMinimap()
+			updateTripComputer()
+			updateScreenshot()
+			if rl.IsKeyPressed(rl.KeyM) {
+				openWorldMap()
+			} else if rl.IsKeyPressed(rl.KeyP) {
+				openPhotoMode()
+			} else if rl.IsKeyPressed(rl.KeyJ) {
+				openAchievements()
+			}
 		}
+	case WorldMap:
+		updateWorldMap()
+	case SlotScreen:
+		updateSlotScreen()
+	case PhotoMode:
+		updatePhotoMode()
+	case Achievements:
+		updateAchievements()
 	}
 }
 
 func drawGame() {
 	switch currentState {
 	case Menu:
-		rl.ClearBackground(rl.RayWhite)
-		screenW, screenH := rl.GetScreenWidth(), rl.GetScreenHeight()
-		playX, playY := (screenW-200)/2, (screenH-50)/2
-		rl.DrawRectangle(int32(playX), int32(playY), 200, 50, rl.Gray)
-		rl.DrawText("Play", int32(playX+70), int32(playY+10), 30, rl.Black)
-	case Playing:
-		rl.ClearBackground(rl.SkyBlue)
-		camera := rl.Camera3D{
-			Position: rl.Vector3{
-				X: car.position.X - 5,
-				Y: car.position.Y + 2,
-				Z: car.position.Z - 5,
-			},
-			Target:     car.position,
-			Up:         rl.Vector3{Y: 1},
-			Fovy:       45,
-			Projection: rl.CameraPerspective,
-		}
-		rl.BeginMode3D(camera)
-		drawWorld()
-		drawCar()
-		rl.EndMode3D()
-
-		// Draw gear icon (simple square with gear symbol) in top left.
-		rl.DrawRectangle(10, 10, 40, 40, rl.Gray)
-		rl.DrawText("⚙", 20, 10, 32, rl.Black)
-
-		// Draw FPS counter in top right if enabled.
-		screenW := rl.GetScreenWidth()
-		if showFPSCounter {
-			fpsText := fmt.Sprintf("FPS: %d", rl.GetFPS())
-			rl.DrawText(fpsText, int32(screenW)-140, 10, 20, rl.Black)
-			// If speed display is enabled, draw it below the FPS.
-			if showSpeedKmh {
-				speedKmh := car.speed * 3.6
-				speedText := fmt.Sprintf("Speed: %.0f km/h", speedKmh)
-				rl.DrawText(speedText, int32(screenW)-140, 35, 20, rl.Black)
+		renderer.ClearBackground(rl.RayWhite)
+		for i, button := range menuButtons {
+			rect := menuButtonRect(i)
+			if button.enabled() {
+				drawUIButton(rect, button.label, 30)
+			} else {
+				renderer.DrawRectangle(rect, rl.LightGray)
+				drawUITextCentered(button.label, rect, 10, 30, rl.Gray)
 			}
-		} else if showSpeedKmh {
-			speedKmh := car.speed * 3.6
-			speedText := fmt.Sprintf("Speed: %.0f km/h", speedKmh)
-			rl.DrawText(speedText, int32(screenW)-140, 10, 20, rl.Black)
 		}
+	case Playing:
+		drawScene()
+		captureThumbnail()
+		drawHUD()
+		captureScreenshot()
+	case WorldMap:
+		drawWorldMap()
+	case SlotScreen:
+		drawSlotScreen()
+	case PhotoMode:
+		drawPhotoMode()
+	case Achievements:
+		drawAchievements()
+	}
+}
 
-		// Draw settings overlay if open.
-		if showSettingsOverlay {
-			screenW, screenH := rl.GetScreenWidth(), rl.GetScreenHeight()
-			panelX, panelY := (screenW-300)/2, (screenH-250)/2
-			rl.DrawRectangle(int32(panelX), int32(panelY), 300, 250, rl.Fade(rl.LightGray, 0.9))
-			rl.DrawText("Settings", int32(panelX+100), int32(panelY+30), 30, rl.Black)
-			// Toggle FPS button.
-			toggleFPSX, toggleFPSY := panelX+50, panelY+70
-			rl.DrawRectangle(int32(toggleFPSX), int32(toggleFPSY), 200, 40, rl.Gray)
-			toggleFPSText := "FPS: OFF"
-			if showFPSCounter {
-				toggleFPSText = "FPS: ON"
-			}
-			rl.DrawText(toggleFPSText, int32(toggleFPSX+60), int32(toggleFPSY+10), 20, rl.Black)
-			// Toggle Speed button.
-			toggleSpeedX, toggleSpeedY := panelX+50, panelY+130
-			rl.DrawRectangle(int32(toggleSpeedX), int32(toggleSpeedY), 200, 40, rl.Gray)
-			toggleSpeedText := "Speed: OFF"
-			if showSpeedKmh {
-				toggleSpeedText = "Speed: ON"
-			}
-			rl.DrawText(toggleSpeedText, int32(toggleSpeedX+50), int32(toggleSpeedY+10), 20, rl.Black)
-			// Return to Main Menu button.
-			returnX, returnY := panelX+50, panelY+190
-			rl.DrawRectangle(int32(returnX), int32(returnY), 200, 40, rl.Gray)
-			rl.DrawText("Return to Main Menu", int32(returnX+10), int32(returnY+10), 20, rl.Black)
+// drawHUD draws the gauges, panels, minimap and settings overlay over the scene.
+func drawHUD() {
+	// Draw gear icon (simple square with gear symbol) in top left.
+	gear := gearIconRect()
+	renderer.DrawRectangle(gear, rl.Gray)
+	drawUIText("⚙", gear.X+ui(10), gear.Y, 32, rl.Black)
+
+	// Draw FPS counter, speed and car status in top right.
+	var hudLines []string
+	if showFPSCounter {
+		hudLines = append(hudLines, fmt.Sprintf("FPS: %d", rl.GetFPS()))
+	}
+	if showSpeedKmh {
+		hudLines = append(hudLines, "Speed: "+formatSpeed(car.speed))
+	}
+	hudLines = append(hudLines,
+		fmt.Sprintf("Fuel: %.0f%%", car.fuel*100),
+		fmt.Sprintf("Damage: %.0f%%", car.damage),
+		weatherLabel())
+	if s := score(); s > 0 {
+		hudLines = append(hudLines, fmt.Sprintf("Score: %d", s))
+	}
+	if wanted > 0 {
+		hudLines = append(hudLines, wantedLabel())
+	}
+	for i, line := range hudLines {
+		pos := uiRect(AnchorTopRight, 10, float32(10+25*i), 150, 20)
+		drawUIText(line, pos.X, pos.Y, 20, rl.Black)
+	}
+
+	drawTripComputer()
+	drawMissionPanel()
+	drawMinimap()
+	drawRaceHUD()
+	drawNotification()
+
+	// Draw settings overlay if open.
+	if showSettingsOverlay {
+		panel := settingsPanelRect()
+		renderer.DrawRectangle(panel, rl.Fade(rl.LightGray, 0.9))
+		drawUITextCentered("Settings", panel, 20, 30, rl.Black)
+		for i, button := range settingsButtons {
+			drawUIButton(settingsButtonRect(i), button.label(), 20)
 		}
 	}
 }
+
+// drawScene draws the 3D world from the current camera.
+func drawScene() {
+	renderer.ClearBackground(clearColor())
+	applyLighting()
+	applyFog()
+	renderer.BeginMode3D(camera)
+	drawSky()
+	drawWorld()
+	drawCar()
+	drawTraffic()
+	drawPedestrians()
+	drawPolice()
+	drawLandmarks()
+	drawCollectibles()
+	drawRaceCheckpoints()
+	drawMissions()
+	// Translucent, so after everything solid.
+	drawGhost()
+	drawLamps()
+	drawParticles()
+	drawWeather()
+	renderer.EndMode3D()
+}
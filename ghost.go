@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Seconds between recorded ghost samples; playback interpolates between them.
+const GHOST_SAMPLE_INTERVAL float32 = 0.1
+
+// The ghost of each route is kept in this directory inside SAVE_DIR.
+const GHOST_DIR = "ghosts"
+
+// G writes the ghost of the current route here, relative to the working directory.
+const GHOST_EXPORT_DIR = "ghosts"
+
+// Version of the ghost file format written by this build.
+const GHOST_VERSION = 1
+
+// ghostRun is a recorded race, as stored in ghost files.
+type ghostRun struct {
+	Version int           `json:"version"`
+	Seed    int64         `json:"seed"`
+	StartX  int           `json:"startX"`
+	StartY  int           `json:"startY"`
+	Time    float32       `json:"time"`
+	Samples []ghostSample `json:"samples"`
+}
+
+// ghostSample is the car's pose at a point of a run.
+type ghostSample struct {
+	T        float32 `json:"t"`
+	X        float32 `json:"x"`
+	Y        float32 `json:"y"`
+	Z        float32 `json:"z"`
+	Yaw      float32 `json:"yaw"`
+	Pitch    float32 `json:"pitch"`
+	Progress float32 `json:"p"` // meters along the route
+}
+
+var (
+	// Ghost raced against on the current route, if there is one.
+	raceGhost *ghostRun
+	// Samples of the race in progress.
+	ghostRecording []ghostSample
+)
+
+// ghostFileName is the file name of the ghost of the route starting at start.
+func ghostFileName(start Coord) string {
+	return fmt.Sprintf("%d_%d_%d.json", worldSeed, start.X, start.Y)
+}
+
+// ghostPath returns where the ghost of a route is kept.
+func ghostPath(start Coord) string {
+	return filepath.Join(SAVE_DIR, GHOST_DIR, ghostFileName(start))
+}
+
+// readGhost loads a ghost file.
+func readGhost(path string) (*ghostRun, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run ghostRun
+	if err := json.Unmarshal(bytes, &run); err != nil {
+		return nil, err
+	}
+	if run.Version > GHOST_VERSION {
+		return nil, fmt.Errorf("ghost version %d is newer than supported version %d", run.Version, GHOST_VERSION)
+	}
+	if len(run.Samples) == 0 {
+		return nil, fmt.Errorf("ghost has no samples")
+	}
+	return &run, nil
+}
+
+// writeGhost stores a ghost file, creating its directory.
+func writeGhost(path string, run *ghostRun) error {
+	bytes, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0o644)
+}
+
+// loadGhost returns the ghost of the route starting at start, or nil if there is none yet.
+func loadGhost(start Coord) *ghostRun {
+	run, err := readGhost(ghostPath(start))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			rl.TraceLog(rl.LogWarning, "could not load ghost: %v", err)
+		}
+		return nil
+	}
+	return run
+}
+
+// routeProgress returns how far along the route pos is, in meters, while
+// heading for checkpoint next. Legs are straight, so this projects pos on the current leg.
+func routeProgress(pos rl.Vector3, next int) float32 {
+	var done float32
+	from := chunkCenter(route.Start)
+	for i := 0; i < next && i < len(route.Checkpoints); i++ {
+		done += rl.Vector3Distance(from, route.Checkpoints[i])
+		from = route.Checkpoints[i]
+	}
+	if next >= len(route.Checkpoints) {
+		return done
+	}
+	leg := rl.Vector3Subtract(route.Checkpoints[next], from)
+	length := rl.Vector3Length(leg)
+	along := rl.Vector3DotProduct(rl.Vector3Subtract(pos, from), rl.Vector3Scale(leg, 1/length))
+	return done + rl.Clamp(along, 0, length)
+}
+
+// appendGhostSample records the car's pose at the current race time.
+func appendGhostSample() {
+	ghostRecording = append(ghostRecording, ghostSample{
+		T: raceTimer, X: car.position.X, Y: car.position.Y, Z: car.position.Z,
+		Yaw: car.yaw, Pitch: car.pitch, Progress: routeProgress(car.position, raceNext),
+	})
+}
+
+// recordGhost samples the car every GHOST_SAMPLE_INTERVAL seconds of the race.
+func recordGhost() {
+	if n := len(ghostRecording); n > 0 && raceTimer < ghostRecording[n-1].T+GHOST_SAMPLE_INTERVAL {
+		return
+	}
+	appendGhostSample()
+}
+
+// saveGhost keeps the finished run as the route's ghost if it beats the current one.
+func saveGhost() {
+	appendGhostSample()
+	if raceGhost != nil && raceGhost.Time <= raceTimer {
+		return
+	}
+	run := &ghostRun{Version: GHOST_VERSION, Seed: worldSeed, StartX: route.Start.X, StartY: route.Start.Y, Time: raceTimer, Samples: ghostRecording}
+	if err := writeGhost(ghostPath(route.Start), run); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not save ghost: %v", err)
+	}
+}
+
+// ghostPose interpolates the ghost's position, yaw and pitch at race time t.
+// After the end of the run the ghost waits at the finish.
+func ghostPose(run *ghostRun, t float32) (rl.Vector3, float32, float32) {
+	samples := run.Samples
+	i := 1
+	for i < len(samples) && samples[i].T < t {
+		i++
+	}
+	if i >= len(samples) {
+		last := samples[len(samples)-1]
+		return rl.Vector3{X: last.X, Y: last.Y, Z: last.Z}, last.Yaw, last.Pitch
+	}
+	a, b := samples[i-1], samples[i]
+	f := float32(0)
+	if b.T > a.T {
+		f = rl.Clamp((t-a.T)/(b.T-a.T), 0, 1)
+	}
+	position := rl.Vector3Lerp(rl.Vector3{X: a.X, Y: a.Y, Z: a.Z}, rl.Vector3{X: b.X, Y: b.Y, Z: b.Z}, f)
+	yaw := a.Yaw + float32(math.Remainder(float64(b.Yaw-a.Yaw), 2*math.Pi))*f
+	return position, yaw, a.Pitch + (b.Pitch-a.Pitch)*f
+}
+
+// ghostDelta returns how far behind (positive) or ahead of the ghost the car
+// is: the race time minus the ghost's time at the same distance along the route.
+func ghostDelta() (float32, bool) {
+	if raceGhost == nil {
+		return 0, false
+	}
+	progress := routeProgress(car.position, raceNext)
+	samples := raceGhost.Samples
+	for i, s := range samples {
+		if s.Progress < progress {
+			continue
+		}
+		if i == 0 {
+			return raceTimer - s.T, true
+		}
+		prev := samples[i-1]
+		f := float32(0)
+		if s.Progress > prev.Progress {
+			f = (progress - prev.Progress) / (s.Progress - prev.Progress)
+		}
+		return raceTimer - (prev.T + (s.T-prev.T)*f), true
+	}
+	return 0, false
+}
+
+// updateGhosts exports the current route's ghost (G) and imports ghost files dropped on the window.
+func updateGhosts() {
+	if rl.IsKeyPressed(rl.KeyG) {
+		start := getChunkCoord(car.position)
+		if raceState != RaceOff {
+			start = route.Start
+		}
+		exportGhost(start)
+	}
+	if rl.IsFileDropped() {
+		for _, path := range rl.LoadDroppedFiles() {
+			importGhost(path)
+		}
+	}
+}
+
+// exportGhost copies the ghost of a route to GHOST_EXPORT_DIR to be shared.
+func exportGhost(start Coord) {
+	run := loadGhost(start)
+	if run == nil {
+		notify("No ghost for this route yet")
+		return
+	}
+	path := filepath.Join(GHOST_EXPORT_DIR, "drive3d_ghost_"+ghostFileName(start))
+	if err := writeGhost(path, run); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not export ghost: %v", err)
+		notify("Ghost export failed")
+		return
+	}
+	notify("Ghost exported to " + path)
+}
+
+// importGhost makes a ghost file the ghost of its route when it is from this
+// world and faster than the ghost already there.
+func importGhost(path string) {
+	run, err := readGhost(path)
+	if err != nil {
+		rl.TraceLog(rl.LogWarning, "could not import ghost %s: %v", path, err)
+		notify("Not a ghost file")
+		return
+	}
+	if run.Seed != worldSeed {
+		notify("Ghost is from another world")
+		return
+	}
+	start := Coord{run.StartX, run.StartY}
+	if current := loadGhost(start); current != nil && current.Time <= run.Time {
+		notify("The ghost on this route is already faster")
+		return
+	}
+	if err := writeGhost(ghostPath(start), run); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not save ghost: %v", err)
+		notify("Ghost import failed")
+		return
+	}
+	notify(fmt.Sprintf("Imported ghost for route %d,%d: %s", start.X, start.Y, formatRaceTime(run.Time)))
+}
+
+// drawGhost draws the ghost car, translucent, where it was at the current race time.
+func drawGhost() {
+	if raceGhost == nil || raceState != RaceRunning {
+		return
+	}
+	position, yaw, pitch := ghostPose(raceGhost, raceTimer)
+	transform := rl.MatrixMultiply(rl.MatrixRotateX(-pitch), rl.MatrixRotateY(math.Pi/2-yaw))
+	transform = rl.MatrixMultiply(transform, rl.MatrixTranslate(position.X, position.Y, position.Z))
+	renderer.DrawModel(car.model, []rl.Matrix{transform}, rl.Fade(rl.SkyBlue, 0.4))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Mass of the car in kg; light props take speed off it in proportion to their weight.
+const CAR_MASS float32 = 1200
+
+// Knocked props are thrown at this share of the car's speed, and tip over at
+// KNOCK_TIP_SPEED (radians per second, faster on harder hits).
+const (
+	KNOCK_THROW     float32 = 0.6
+	KNOCK_TIP_SPEED float32 = 3
+	KNOCK_FRICTION  float32 = 4 // m/s² while sliding on the ground
+	KNOCK_GRAVITY   float32 = 9.8
+)
+
+// propRef points at a prop in a loaded chunk.
+type propRef struct {
+	Chunk Coord
+	Index int
+}
+
+// Props still falling or sliding. A knocked prop stays down after it comes
+// to rest, until its chunk is unloaded and rebuilt.
+var fallingProps []propRef
+
+// knockProps knocks over the light props the car at pos runs into, taking
+// their colliders away before the collision check and slowing the car.
+func knockProps(pos rl.Vector3) {
+	forward := carForward()
+	if car.speed < 0 {
+		forward = rl.Vector3Negate(forward)
+	}
+	speed := float32(math.Abs(float64(car.speed)))
+	center := getChunkCoord(pos)
+	for i := center.X - 1; i <= center.X+1; i++ {
+		for j := center.Y - 1; j <= center.Y+1; j++ {
+			chunk := chunks[Coord{i, j}]
+			if chunk == nil {
+				continue
+			}
+			for k := range chunk.Props {
+				p := &chunk.Props[k]
+				def := propDefs[p.Type]
+				if def.Mass != MassLight || p.Knocked {
+					continue
+				}
+				// The same circle test as checkCollisions.
+				dx, dz := p.Position.X-pos.X, p.Position.Z-pos.Z
+				reach := 1 + def.Size.X/2
+				if dx*dx+dz*dz >= reach*reach {
+					continue
+				}
+
+				removeCollider(chunk, p.bounds())
+				// Momentum is shared: a heavier prop flies off slower and slows the car more.
+				share := CAR_MASS / (CAR_MASS + def.Weight)
+				p.Knocked = true
+				p.Velocity = rl.Vector3Scale(forward, speed*(1+share)*KNOCK_THROW)
+				p.Velocity.Y = 1 + speed*0.1
+				p.TiltAxis = rl.Vector3Normalize(rl.Vector3{X: forward.Z, Z: -forward.X})
+				p.TiltSpeed = KNOCK_TIP_SPEED * (1 + speed/20)
+				car.speed *= share
+				burst(particles, particleRand, ParticleDust, 8, rl.Vector3Add(p.Position, rl.Vector3{Y: 0.5}))
+				fallingProps = append(fallingProps, propRef{Chunk: chunk.Coord, Index: k})
+			}
+		}
+	}
+}
+
+// updateKnockedProps moves falling props: they fly, land, slide to a stop
+// and tip over until they lie flat.
+func updateKnockedProps() {
+	dt := rl.GetFrameTime()
+	kept := fallingProps[:0]
+	for _, ref := range fallingProps {
+		// Gone with its chunk; a rebuilt chunk has its props standing again.
+		chunk := chunks[ref.Chunk]
+		if chunk == nil || !chunk.Props[ref.Index].Knocked {
+			continue
+		}
+		p := &chunk.Props[ref.Index]
+
+		p.Velocity.Y -= KNOCK_GRAVITY * dt
+		p.Position = rl.Vector3Add(p.Position, rl.Vector3Scale(p.Velocity, dt))
+		if p.Position.Y <= 0 {
+			p.Position.Y, p.Velocity.Y = 0, 0
+			flat := float32(math.Hypot(float64(p.Velocity.X), float64(p.Velocity.Z)))
+			if flat > 0 {
+				slowed := float32(math.Max(0, float64(flat-KNOCK_FRICTION*dt)))
+				p.Velocity.X *= slowed / flat
+				p.Velocity.Z *= slowed / flat
+			}
+		}
+		p.Tilt = float32(math.Min(math.Pi/2, float64(p.Tilt+p.TiltSpeed*dt)))
+
+		p.Transform = rl.MatrixMultiply(
+			rl.MatrixMultiply(propDefs[p.Type].local, rl.MatrixRotate(p.TiltAxis, p.Tilt)),
+			rl.MatrixTranslate(p.Position.X, p.Position.Y, p.Position.Z))
+		resting := p.Position.Y == 0 && p.Velocity.X == 0 && p.Velocity.Z == 0 && p.Tilt >= math.Pi/2
+		if !resting {
+			kept = append(kept, ref)
+		}
+	}
+	fallingProps = kept
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Landmarks are rare one-off features built into a chunk of their host type.
+const (
+	LandmarkNone = iota
+	LandmarkGasStation
+	LandmarkBridge
+	LandmarkStadium
+	LandmarkRadioTower
+	LandmarkFrozenLake
+)
+
+// Fuel pumped per second at a gas station, as a fraction of a tank.
+const REFUEL_RATE float32 = 0.25
+
+// landmarkDef describes a landmark and where it may appear: only in chunks of
+// type Host whose neighbors, generated before or after it, are all of the Neighbors types.
+type landmarkDef struct {
+	Name      string
+	Host      int
+	Neighbors []int
+	Chance    float32
+	Color     rl.Color // map marker
+	// build adds the landmark's pieces to a chunk; sx and sz pick the quadrant it uses.
+	build func(chunk *Chunk, center rl.Vector3, sx, sz float32, r *rand.Rand)
+	// draw adds anything that isn't a piece; optional.
+	draw func(chunk *Chunk)
+}
+
+var landmarkDefs = []landmarkDef{
+	LandmarkNone: {},
+	LandmarkGasStation: {Name: "Gas Station", Host: Highway, Neighbors: []int{Highway, Desert, Forest, Snow},
+		Chance: 0.08, Color: rl.Orange, build: buildGasStation},
+	LandmarkBridge: {Name: "Canyon Bridge", Host: Desert, Neighbors: []int{Desert, Highway},
+		Chance: 0.08, Color: rl.Brown, build: buildBridge},
+	LandmarkStadium: {Name: "Stadium", Host: City, Neighbors: []int{City, Commercial},
+		Chance: 0.06, Color: rl.Lime, build: buildStadium},
+	LandmarkRadioTower: {Name: "Radio Tower", Host: Forest, Neighbors: []int{Forest, Highway},
+		Chance: 0.06, Color: rl.Red, build: buildRadioTower, draw: drawRadioTower},
+	LandmarkFrozenLake: {Name: "Frozen Lake", Host: Snow, Neighbors: []int{Snow},
+		Chance: 0.1, Color: rl.SkyBlue, build: buildFrozenLake},
+}
+
+// landmarkPiece is a box of a landmark; solid pieces get colliders.
+type landmarkPiece struct {
+	Transform rl.Matrix // places pieceMesh
+	Color     rl.Color
+}
+
+// The chunk the car was last in, so entering a landmark is announced once.
+var lastLandmarkChunk Coord
+
+// chooseLandmark decides whether a newly generated chunk holds a landmark.
+func chooseLandmark(i, j, chunkType int) int {
+	if i == 0 && j == 0 {
+		return LandmarkNone
+	}
+	roll := seededRandom("landmark", i, j).Float32()
+	for id, def := range landmarkDefs {
+		if id == LandmarkNone || def.Host != chunkType || !neighborsAllow(i, j, def.Neighbors) {
+			continue
+		}
+		if roll < def.Chance {
+			return id
+		}
+		roll -= def.Chance
+	}
+	return LandmarkNone
+}
+
+// neighborsAllow reports whether every generated neighbor of (i,j) is one of
+// the allowed types. Neighbors generated later keep to the rule too; see
+// determineChunkType.
+func neighborsAllow(i, j int, allowed []int) bool {
+	for _, n := range []Coord{{i - 1, j}, {i + 1, j}, {i, j - 1}, {i, j + 1}} {
+		if seen, ok := knownChunk(n); ok && !slices.Contains(allowed, seen.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildLandmark adds the chunk's landmark, reserving its ground and marking it on the maps.
+func buildLandmark(chunk *Chunk) {
+	def := landmarkDefs[chunk.Landmark]
+	r := seededRandom("landmark-build", chunk.Coord.X, chunk.Coord.Y)
+	sx, sz := float32(2*r.Intn(2)-1), float32(2*r.Intn(2)-1)
+	center := chunkCenter(chunk.Coord)
+	chunk.LandmarkSpot = center
+	def.build(chunk, center, sx, sz, r)
+	chunk.Markers = append(chunk.Markers, mapMarker{Position: chunk.LandmarkSpot, Color: def.Color})
+}
+
+// addPiece adds a box to a chunk's landmark. Solid pieces are split into
+// square colliders, since collisions treat each collider as a circle.
+func addPiece(chunk *Chunk, center, size rl.Vector3, color rl.Color, solid bool) {
+	transform := rl.MatrixMultiply(rl.MatrixScale(size.X, size.Y, size.Z), rl.MatrixTranslate(center.X, center.Y, center.Z))
+	chunk.Pieces = append(chunk.Pieces, landmarkPiece{Transform: transform, Color: color})
+	if !solid {
+		return
+	}
+	side := float32(math.Min(float64(size.X), float64(size.Z)))
+	count := int(math.Ceil(float64(float32(math.Max(float64(size.X), float64(size.Z))) / side)))
+	for k := 0; k < count; k++ {
+		part := rl.Vector3{X: size.X, Y: size.Y, Z: size.Z}
+		at := center
+		if size.X > size.Z {
+			part.X = size.X / float32(count)
+			at.X = center.X - size.X/2 + part.X*(float32(k)+0.5)
+		} else {
+			part.Z = size.Z / float32(count)
+			at.Z = center.Z - size.Z/2 + part.Z*(float32(k)+0.5)
+		}
+		chunk.Colliders = append(chunk.Colliders, rl.BoundingBox{
+			Min: rl.Vector3{X: at.X - part.X/2, Y: center.Y - size.Y/2, Z: at.Z - part.Z/2},
+			Max: rl.Vector3{X: at.X + part.X/2, Y: center.Y + size.Y/2, Z: at.Z + part.Z/2},
+		})
+	}
+}
+
+// reserve keeps random props out of a rectangle given by two corners.
+func reserve(chunk *Chunk, x1, z1, x2, z2 float32) {
+	chunk.Reserved = append(chunk.Reserved, rl.BoundingBox{
+		Min: rl.Vector3{X: float32(math.Min(float64(x1), float64(x2))), Z: float32(math.Min(float64(z1), float64(z2)))},
+		Max: rl.Vector3{X: float32(math.Max(float64(x1), float64(x2))), Z: float32(math.Max(float64(z1), float64(z2)))},
+	})
+}
+
+// isReserved reports whether (x, z) is ground taken by the chunk's landmark.
+func isReserved(chunk *Chunk, x, z float32) bool {
+	for _, box := range chunk.Reserved {
+		if x >= box.Min.X && x <= box.Max.X && z >= box.Min.Z && z <= box.Max.Z {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGasStation puts pumps under a canopy beside the north-south road,
+// with the shop behind. Stopping in the lane by the pumps refuels.
+func buildGasStation(chunk *Chunk, c rl.Vector3, sx, sz float32, r *rand.Rand) {
+	pumpX, z := c.X+sx*5.5, c.Z+sz*10
+	for _, dz := range []float32{-2, 2} {
+		addPiece(chunk, rl.Vector3{X: pumpX, Y: 0.75, Z: z + dz}, rl.Vector3{X: 0.8, Y: 1.5, Z: 0.8}, rl.Red, true)
+	}
+	for _, dz := range []float32{-3.5, 3.5} {
+		addPiece(chunk, rl.Vector3{X: pumpX, Y: 2, Z: z + dz}, rl.Vector3{X: 0.4, Y: 4, Z: 0.4}, rl.LightGray, true)
+	}
+	addPiece(chunk, rl.Vector3{X: pumpX, Y: 4.2, Z: z}, rl.Vector3{X: 6, Y: 0.4, Z: 9}, rl.RayWhite, false)
+	addPiece(chunk, rl.Vector3{X: c.X + sx*13, Y: 2, Z: z}, rl.Vector3{X: 6, Y: 4, Z: 6}, rl.Beige, true)
+	reserve(chunk, c.X+sx*ROAD_WIDTH/2, c.Z+sz*ROAD_WIDTH/2, c.X+sx*CHUNK_SIZE/2, c.Z+sz*CHUNK_SIZE/2)
+	chunk.LandmarkSpot = rl.Vector3{X: c.X + sx*(ROAD_WIDTH/2+1.2), Z: z}
+}
+
+// buildBridge cuts a canyon across the chunk, east to west, crossed by a
+// bridge on the north-south road.
+func buildBridge(chunk *Chunk, c rl.Vector3, sx, sz float32, r *rand.Rand) {
+	const width = 8
+	z := c.Z + sz*14
+	gap := ROAD_WIDTH/2 + 1
+	length := CHUNK_SIZE/2 - gap
+	canyon := rl.NewColor(60, 40, 25, 255)
+	for _, side := range []float32{-1, 1} {
+		addPiece(chunk, rl.Vector3{X: c.X + side*(gap+length/2), Y: 0.05, Z: z}, rl.Vector3{X: length, Y: 0.1, Z: width}, canyon, true)
+	}
+	addPiece(chunk, rl.Vector3{X: c.X, Y: 0.02, Z: z}, rl.Vector3{X: gap * 2, Y: 0.04, Z: width}, canyon, false)
+	addPiece(chunk, rl.Vector3{X: c.X, Y: 0.08, Z: z}, rl.Vector3{X: ROAD_WIDTH + 1, Y: 0.16, Z: width + 1}, rl.Gray, false)
+	for _, side := range []float32{-1, 1} {
+		addPiece(chunk, rl.Vector3{X: c.X + side*(ROAD_WIDTH/2+0.5), Y: 0.5, Z: z}, rl.Vector3{X: 0.3, Y: 1, Z: width + 1}, rl.DarkGray, false)
+	}
+	reserve(chunk, c.X-CHUNK_SIZE/2, z-width/2-1, c.X+CHUNK_SIZE/2, z+width/2+1)
+	chunk.LandmarkSpot = rl.Vector3{X: c.X, Z: z}
+}
+
+// buildStadium fills the space between the main roads and the backroads of one quadrant.
+func buildStadium(chunk *Chunk, c rl.Vector3, sx, sz float32, r *rand.Rand) {
+	q := rl.Vector3{X: c.X + sx*10.75, Z: c.Z + sz*10.75}
+	addPiece(chunk, rl.Vector3{X: q.X, Y: 4, Z: q.Z}, rl.Vector3{X: 13, Y: 8, Z: 13}, rl.Gray, true)
+	addPiece(chunk, rl.Vector3{X: q.X, Y: 8.05, Z: q.Z}, rl.Vector3{X: 11, Y: 0.1, Z: 11}, rl.DarkGreen, false)
+	for _, corner := range []rl.Vector2{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: 1, Y: 1}} {
+		addPiece(chunk, rl.Vector3{X: q.X + corner.X*6.2, Y: 7, Z: q.Z + corner.Y*6.2}, rl.Vector3{X: 0.3, Y: 14, Z: 0.3}, rl.LightGray, false)
+	}
+	reserve(chunk, q.X-8, q.Z-8, q.X+8, q.Z+8)
+	chunk.LandmarkSpot = q
+}
+
+// buildRadioTower raises a striped mast in a clearing.
+func buildRadioTower(chunk *Chunk, c rl.Vector3, sx, sz float32, r *rand.Rand) {
+	q := rl.Vector3{X: c.X + sx*12.5, Z: c.Z + sz*12.5}
+	addPiece(chunk, rl.Vector3{X: q.X, Y: 0.5, Z: q.Z}, rl.Vector3{X: 3, Y: 1, Z: 3}, rl.Gray, true)
+	for k := 0; k < 5; k++ {
+		color := rl.Red
+		if k%2 == 1 {
+			color = rl.White
+		}
+		addPiece(chunk, rl.Vector3{X: q.X, Y: 1 + 8*float32(k) + 4, Z: q.Z}, rl.Vector3{X: 0.8, Y: 8, Z: 0.8}, color, false)
+	}
+	reserve(chunk, q.X-10, q.Z-10, q.X+10, q.Z+10)
+	chunk.LandmarkSpot = q
+}
+
+// drawRadioTower draws the guy wires and the blinking light at the top.
+func drawRadioTower(chunk *Chunk) {
+	q := chunk.LandmarkSpot
+	top := rl.Vector3{X: q.X, Y: 35, Z: q.Z}
+	for _, anchor := range []rl.Vector2{{X: 8}, {X: -8}, {Y: 8}, {Y: -8}} {
+		renderer.DrawLine3D(top, rl.Vector3{X: q.X + anchor.X, Z: q.Z + anchor.Y}, rl.DarkGray)
+	}
+	if int(rl.GetTime())%2 == 0 {
+		renderer.DrawSphere(rl.Vector3{X: q.X, Y: 41.5, Z: q.Z}, 0.6, rl.Red)
+	}
+}
+
+// buildFrozenLake covers the chunk outside the roads with ice, dotted with
+// ice fishing huts.
+func buildFrozenLake(chunk *Chunk, c rl.Vector3, sx, sz float32, r *rand.Rand) {
+	ice := rl.NewColor(200, 230, 245, 255)
+	inner, outer := ROAD_WIDTH/2+0.5, CHUNK_SIZE/2-1
+	for _, corner := range []rl.Vector2{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: 1, Y: 1}} {
+		middle := (inner + outer) / 2
+		addPiece(chunk, rl.Vector3{X: c.X + corner.X*middle, Y: 0.03, Z: c.Z + corner.Y*middle}, rl.Vector3{X: outer - inner, Y: 0.02, Z: outer - inner}, ice, false)
+		reserve(chunk, c.X+corner.X*inner, c.Z+corner.Y*inner, c.X+corner.X*outer, c.Z+corner.Y*outer)
+	}
+	for k := 0; k < 3; k++ {
+		x := c.X + sx*(inner+3+r.Float32()*(outer-inner-6))
+		z := c.Z + sz*(inner+3+r.Float32()*(outer-inner-6))
+		addPiece(chunk, rl.Vector3{X: x, Y: 1, Z: z}, rl.Vector3{X: 2, Y: 2, Z: 2}, rl.Brown, true)
+		sx, sz = -sz, sx // next hut in the next quadrant
+	}
+}
+
+// updateLandmarks announces landmarks as the car reaches them and runs the gas station pumps.
+func updateLandmarks() {
+	coord := getChunkCoord(car.position)
+	chunk := chunks[coord]
+	if chunk == nil || chunk.Landmark == LandmarkNone {
+		lastLandmarkChunk = coord
+		return
+	}
+	if coord != lastLandmarkChunk {
+		lastLandmarkChunk = coord
+		notify(landmarkDefs[chunk.Landmark].Name)
+	}
+	if chunk.Landmark == LandmarkGasStation && car.fuel < 1 && stoppedAt(chunk.LandmarkSpot) {
+		car.fuel = float32(math.Min(1, float64(car.fuel+REFUEL_RATE*rl.GetFrameTime())))
+		notify(fmt.Sprintf("Refueling %.0f%%", car.fuel*100))
+	}
+}
+
+// drawLandmarks draws the landmarks of the chunks around the car, batching
+// pieces by color so they are lit and fogged like the rest of the world.
+func drawLandmarks() {
+	var colors []rl.Color
+	batches := map[rl.Color][]rl.Matrix{}
+	center := getChunkCoord(car.position)
+	for i := center.X - 2; i <= center.X+2; i++ {
+		for j := center.Y - 2; j <= center.Y+2; j++ {
+			chunk := chunks[Coord{i, j}]
+			if chunk == nil || chunk.Landmark == LandmarkNone {
+				continue
+			}
+			for _, piece := range chunk.Pieces {
+				if _, ok := batches[piece.Color]; !ok {
+					colors = append(colors, piece.Color)
+				}
+				batches[piece.Color] = append(batches[piece.Color], piece.Transform)
+			}
+			if draw := landmarkDefs[chunk.Landmark].draw; draw != nil {
+				draw(chunk)
+			}
+		}
+	}
+	for _, color := range colors {
+		pieceMaterial.Maps.Color = color
+		renderer.DrawMeshes(pieceMesh, pieceMaterial, batches[color])
+	}
+}
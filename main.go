@@ -8,7 +8,7 @@ import (
 )
 
 func main() {
-	rl.SetConfigFlags(rl.FlagWindowResizable)
+	rl.SetConfigFlags(rl.FlagWindowResizable | rl.FlagWindowHighdpi)
 	rl.InitWindow(800, 600, "3D Racing Game")
 	defer rl.CloseWindow()
 	rl.SetTargetFPS(60)
@@ -20,4 +20,9 @@ func main() {
 		drawGame()
 		rl.EndDrawing()
 	}
+	if sessionActive {
+		if err := writeSave(AUTOSAVE_SLOT); err != nil {
+			rl.TraceLog(rl.LogWarning, "could not save game: %v", err)
+		}
+	}
 }
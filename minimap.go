@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Side length of the minimap in pixels.
+const MINIMAP_SIZE = 180
+
+// mapMarker is a point of interest drawn on the maps.
+type mapMarker struct {
+	Position rl.Vector3
+	Color    rl.Color
+}
+
+var (
+	// Minimap zoom levels in pixels per meter; Z cycles through them.
+	minimapZoomLevels = []float32{1.2, 0.5}
+	minimapZoom       int
+	// When true the minimap keeps north (-Z) at the top instead of rotating with the car.
+	minimapNorthUp bool = false
+)
+
+// updateMinimap handles the minimap zoom toggle.
+func updateMinimap() {
+	if rl.IsKeyPressed(rl.KeyZ) {
+		minimapZoom = (minimapZoom + 1) % len(minimapZoomLevels)
+	}
+}
+
+// minimapRotation returns the angle (radians) that turns world XZ into minimap space.
+// In heading-up mode the car's forward direction always points to the top.
+func minimapRotation() float32 {
+	if minimapNorthUp {
+		return 0
+	}
+	return -math.Pi/2 - car.yaw
+}
+
+// worldToMinimap projects a world XZ position onto the minimap centered on the car.
+func worldToMinimap(x, z float32, center rl.Vector2, scale, rotation float32) rl.Vector2 {
+	dx := (x - car.position.X) * scale
+	dz := (z - car.position.Z) * scale
+	sin := float32(math.Sin(float64(rotation)))
+	cos := float32(math.Cos(float64(rotation)))
+	return rl.Vector2{X: center.X + dx*cos - dz*sin, Y: center.Y + dx*sin + dz*cos}
+}
+
+// drawMapRect draws a world-space rectangle centered at (x,z) rotated into map space.
+func drawMapRect(x, z, width, length float32, center rl.Vector2, scale, rotation float32, color rl.Color) {
+	pos := worldToMinimap(x, z, center, scale, rotation)
+	w, h := width*scale, length*scale
+	rec := rl.Rectangle{X: pos.X, Y: pos.Y, Width: w, Height: h}
+	renderer.DrawRectanglePro(rec, rl.Vector2{X: w / 2, Y: h / 2}, rotation*rl.Rad2deg, color)
+}
+
+// drawCarArrow draws a triangle at pos pointing along angle (radians, screen space).
+func drawCarArrow(pos rl.Vector2, angle, size float32, color rl.Color) {
+	fwd := rl.Vector2{X: float32(math.Cos(float64(angle))), Y: float32(math.Sin(float64(angle)))}
+	left := rl.Vector2{X: fwd.Y, Y: -fwd.X}
+	tip := rl.Vector2{X: pos.X + fwd.X*size, Y: pos.Y + fwd.Y*size}
+	back := rl.Vector2{X: pos.X - fwd.X*size*0.6, Y: pos.Y - fwd.Y*size*0.6}
+	l := rl.Vector2{X: back.X + left.X*size*0.6, Y: back.Y + left.Y*size*0.6}
+	r := rl.Vector2{X: back.X - left.X*size*0.6, Y: back.Y - left.Y*size*0.6}
+	renderer.DrawTriangle(tip, l, r, color)
+}
+
+// drawMinimap renders the chunks around the car in the bottom-right corner.
+func drawMinimap() {
+	rect := uiRect(AnchorBottomRight, 10, 10, MINIMAP_SIZE, MINIMAP_SIZE)
+	center := rl.Vector2{X: rect.X + rect.Width/2, Y: rect.Y + rect.Height/2}
+	scale := ui(minimapZoomLevels[minimapZoom])
+	rotation := minimapRotation()
+
+	renderer.BeginScissorMode(rect)
+	renderer.DrawRectangle(rect, rl.Black)
+
+	// Enough chunks to cover the corners of the map when rotated.
+	radius := int(MINIMAP_SIZE*0.71/(CHUNK_SIZE*minimapZoomLevels[minimapZoom])) + 1
+	playerChunk := getChunkCoord(car.position)
+	var markers []mapMarker
+	for i := playerChunk.X - radius; i <= playerChunk.X+radius; i++ {
+		for j := playerChunk.Y - radius; j <= playerChunk.Y+radius; j++ {
+			// Chunks beyond the unload radius are drawn as the world map remembers them.
+			chunk, exists := chunks[Coord{i, j}]
+			if !exists {
+				seen, ok := discovered[Coord{i, j}]
+				if !ok {
+					continue
+				}
+				chunk = &Chunk{Type: seen.Type, RoadType: seen.RoadType, Coord: Coord{i, j}}
+			}
+			cx := float32(i)*CHUNK_SIZE + CHUNK_SIZE/2
+			cz := float32(j)*CHUNK_SIZE + CHUNK_SIZE/2
+			drawMapRect(cx, cz, CHUNK_SIZE, CHUNK_SIZE, center, scale, rotation, typeColors[chunk.Type])
+			for _, road := range chunkRoads(chunk) {
+				drawMapRect(road.X, road.Z, road.Width, road.Length, center, scale, rotation, roadColors[chunk.RoadType])
+			}
+			markers = append(markers, chunk.Markers...)
+		}
+	}
+	markers = append(markers, raceMarkers()...)
+	markers = append(markers, missionMarkers()...)
+	markers = append(markers, policeMarkers()...)
+	for _, marker := range markers {
+		renderer.DrawCircle(worldToMinimap(marker.Position.X, marker.Position.Z, center, scale, rotation), ui(4), marker.Color)
+	}
+	drawCarArrow(center, car.yaw+rotation, ui(8), rl.Red)
+	renderer.EndScissorMode()
+
+	renderer.DrawRectangleLines(rect, 1, rl.Black)
+	if minimapNorthUp {
+		drawUITextCentered("N", rect, 4, 20, rl.White)
+	}
+}
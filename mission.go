@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Chance that a City or Commercial chunk has a job waiting.
+const MISSION_OFFER_CHANCE float32 = 0.4
+
+// Jobs are picked up and handed over by stopping within this distance of the marker.
+const MISSION_STOP_RADIUS float32 = 6
+
+// Drop-offs are this many chunks away along a road, and up to one chunk to the side.
+const (
+	MISSION_MIN_CHUNKS = 2
+	MISSION_MAX_CHUNKS = 4
+)
+
+// Time allowed is the road distance at MISSION_PACE (m/s) plus MISSION_MARGIN seconds.
+const (
+	MISSION_PACE   float32 = 12
+	MISSION_MARGIN float32 = 20
+)
+
+// Pay per meter of road distance for a job done with no time to spare and no damage.
+const MISSION_PAY_PER_METER float32 = 0.1
+
+// missionKind is what a job carries.
+type missionKind int
+
+const (
+	MissionDelivery missionKind = iota // a parcel from a Commercial store
+	MissionTaxi                        // a passenger from a City building
+)
+
+var missionNames = []string{MissionDelivery: "Delivery", MissionTaxi: "Taxi"}
+
+// mission is a job from a pickup point to a drop-off a few chunks away.
+type mission struct {
+	Kind      missionKind
+	Origin    Coord
+	Pickup    rl.Vector3
+	Dropoff   rl.Vector3
+	Distance  float32 // by road, in meters
+	TimeLimit float32
+}
+
+var (
+	activeMission *mission
+	// Seconds left on the active job.
+	missionTimer float32
+	// Car damage when the active job was picked up.
+	missionDamage float32
+	// Chunks whose job has been taken this session.
+	missionsTaken = map[Coord]bool{}
+	// Money earned from jobs; stored in save games.
+	cash float32
+)
+
+// missionOffer returns the job waiting in a chunk, if it hasn't been taken.
+func missionOffer(chunk *Chunk) (mission, bool) {
+	if chunk.Offer == nil || missionsTaken[chunk.Coord] {
+		return mission{}, false
+	}
+	return *chunk.Offer, true
+}
+
+// chunkMissionOffer places the job of a newly built chunk, if it has one.
+// Jobs come from the seed, so a chunk always offers the same one.
+func chunkMissionOffer(chunk *Chunk) (mission, bool) {
+	var m mission
+	switch chunk.Type {
+	case Commercial:
+		m.Kind = MissionDelivery
+	case City:
+		m.Kind = MissionTaxi
+	default:
+		return m, false
+	}
+	r := seededRandom("mission", chunk.Coord.X, chunk.Coord.Y)
+	if r.Float32() >= MISSION_OFFER_CHANCE {
+		return m, false
+	}
+	var sites []rl.Vector3
+	for _, prop := range chunk.Props {
+		if prop.Type == PropBuilding || prop.Type == PropStore {
+			sites = append(sites, prop.Position)
+		}
+	}
+	if len(sites) == 0 {
+		return m, false
+	}
+
+	// Pick up in the lane of the main road nearest the building.
+	site := sites[r.Intn(len(sites))]
+	center := chunkCenter(chunk.Coord)
+	m.Origin, m.Pickup = chunk.Coord, site
+	if dx, dz := site.X-center.X, site.Z-center.Z; math.Abs(float64(dx)) < math.Abs(float64(dz)) {
+		m.Pickup.X = center.X + float32(math.Copysign(float64(LANE_OFFSET), float64(dx)))
+	} else {
+		m.Pickup.Z = center.Z + float32(math.Copysign(float64(LANE_OFFSET), float64(dz)))
+	}
+
+	// Drop off on the east arm of the main road of a chunk further on.
+	dir := r.Intn(4)
+	along, side := trafficDirs[dir], trafficDirs[(dir+1)%4]
+	n, offset := MISSION_MIN_CHUNKS+r.Intn(MISSION_MAX_CHUNKS-MISSION_MIN_CHUNKS+1), r.Intn(3)-1
+	dest := Coord{
+		chunk.Coord.X + n*int(along.X) + offset*int(side.X),
+		chunk.Coord.Y + n*int(along.Z) + offset*int(side.Z),
+	}
+	m.Dropoff = rl.Vector3Add(chunkCenter(dest), rl.Vector3{X: CHUNK_SIZE / 4, Z: LANE_OFFSET})
+	m.Distance = float32(math.Abs(float64(m.Dropoff.X-m.Pickup.X)) + math.Abs(float64(m.Dropoff.Z-m.Pickup.Z)))
+	m.TimeLimit = m.Distance/MISSION_PACE + MISSION_MARGIN
+	return m, true
+}
+
+// nearbyOffers returns the jobs waiting in the chunks around the car.
+func nearbyOffers() []mission {
+	var offers []mission
+	center := getChunkCoord(car.position)
+	for i := center.X - 1; i <= center.X+1; i++ {
+		for j := center.Y - 1; j <= center.Y+1; j++ {
+			if chunk := chunks[Coord{i, j}]; chunk != nil {
+				if m, ok := missionOffer(chunk); ok {
+					offers = append(offers, m)
+				}
+			}
+		}
+	}
+	return offers
+}
+
+// stoppedAt reports whether the car is standing within MISSION_STOP_RADIUS of pos.
+func stoppedAt(pos rl.Vector3) bool {
+	dx, dz := car.position.X-pos.X, car.position.Z-pos.Z
+	return math.Abs(float64(car.speed)) < 3 && dx*dx+dz*dz < MISSION_STOP_RADIUS*MISSION_STOP_RADIUS
+}
+
+// updateMissions starts a job when the car stops at a pickup and pays for it
+// at the drop-off; running out of time loses the job.
+func updateMissions() {
+	if activeMission == nil {
+		if raceState != RaceOff {
+			return
+		}
+		for _, m := range nearbyOffers() {
+			if stoppedAt(m.Pickup) {
+				activeMission, missionTimer, missionDamage = &m, m.TimeLimit, car.damage
+				missionsTaken[m.Origin] = true
+				notify(fmt.Sprintf("%s: %s %s, %s", missionNames[m.Kind], formatDistance(m.Distance), compassDirection(m.Dropoff), formatDuration(m.TimeLimit)))
+				return
+			}
+		}
+		return
+	}
+
+	missionTimer -= rl.GetFrameTime()
+	if missionTimer <= 0 {
+		activeMission = nil
+		notify("Out of time, job lost")
+		return
+	}
+	if stoppedAt(activeMission.Dropoff) {
+		// Half pay for a job finished at the buzzer, and a wreck earns nothing.
+		timeFactor := 0.5 + 0.5*missionTimer/activeMission.TimeLimit
+		damageFactor := rl.Clamp(1-(car.damage-missionDamage)/50, 0, 1)
+		pay := activeMission.Distance * MISSION_PAY_PER_METER * timeFactor * damageFactor
+		cash += pay
+		activeMission = nil
+		emitEvent(EventJobDone, pay)
+		notify(fmt.Sprintf("Job done: $%.0f", pay))
+	}
+}
+
+// compassDirection names the direction from the car to pos, north being -Z.
+func compassDirection(pos rl.Vector3) string {
+	angle := math.Atan2(float64(pos.X-car.position.X), float64(car.position.Z-pos.Z))
+	sector := int(math.Round(angle/(math.Pi/4))+8) % 8
+	return []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}[sector]
+}
+
+// missionMarkers returns the drop-off of the active job, or the pickups around the car.
+func missionMarkers() []mapMarker {
+	if activeMission != nil {
+		return []mapMarker{{Position: activeMission.Dropoff, Color: rl.Blue}}
+	}
+	var markers []mapMarker
+	for _, m := range nearbyOffers() {
+		markers = append(markers, mapMarker{Position: m.Pickup, Color: rl.Lime})
+	}
+	return markers
+}
+
+// drawMissions marks pickups and the drop-off with a pad and a beacon.
+func drawMissions() {
+	for _, marker := range missionMarkers() {
+		renderer.DrawCube(rl.Vector3{X: marker.Position.X, Y: 0.05, Z: marker.Position.Z}, rl.Vector3{X: 4, Y: 0.1, Z: 4}, rl.Fade(marker.Color, 0.6))
+		renderer.DrawCube(rl.Vector3{X: marker.Position.X, Y: 10, Z: marker.Position.Z}, rl.Vector3{X: 0.5, Y: 20, Z: 0.5}, rl.Fade(marker.Color, 0.35))
+	}
+}
+
+// drawMissionPanel shows the active job and earnings in the bottom-left corner.
+func drawMissionPanel() {
+	var lines []string
+	if m := activeMission; m != nil {
+		lines = append(lines,
+			fmt.Sprintf("%s  %s left", missionNames[m.Kind], formatDuration(missionTimer)),
+			fmt.Sprintf("Drop-off: %s %s", formatDistance(rl.Vector3Distance(car.position, m.Dropoff)), compassDirection(m.Dropoff)))
+	}
+	if cash > 0 || len(lines) > 0 {
+		lines = append(lines, fmt.Sprintf("Cash: $%.0f", cash))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	panel := uiRect(AnchorBottomLeft, 10, 10, 220, float32(10+20*len(lines)))
+	renderer.DrawRectangle(panel, rl.Fade(rl.LightGray, 0.8))
+	for i, line := range lines {
+		drawUIText(line, panel.X+ui(5), panel.Y+ui(float32(5+20*i)), 18, rl.Black)
+	}
+}
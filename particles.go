@@ -0,0 +1,214 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Size of the shared particle pool; emitters stop spawning when it is full.
+const MAX_PARTICLES = 2000
+
+// ParticleKind identifies a particle effect.
+type ParticleKind int
+
+const (
+	ParticleDust ParticleKind = iota
+	ParticleSnow
+	ParticleSand
+	ParticleSpark
+	ParticleSmoke
+)
+
+// particleDef describes how particles of a kind look and move.
+type particleDef struct {
+	Color    rl.Color
+	Size     float32 // edge length at birth
+	Growth   float32 // size multiplier reached at the end of life
+	Life     float32 // seconds
+	Gravity  float32 // vertical acceleration; positive rises
+	Drag     float32 // fraction of velocity lost per second
+	Spread   float32 // random velocity added in each direction
+	PerMeter float32 // particles per meter driven for surface effects
+}
+
+var particleDefs = []particleDef{
+	ParticleDust:  {Color: rl.NewColor(150, 120, 80, 200), Size: 0.3, Growth: 4, Life: 1.2, Gravity: -1, Drag: 1.5, Spread: 1, PerMeter: 3},
+	ParticleSnow:  {Color: rl.NewColor(240, 245, 255, 220), Size: 0.15, Growth: 2, Life: 0.8, Gravity: -4, Drag: 1, Spread: 1.5, PerMeter: 4},
+	ParticleSand:  {Color: rl.NewColor(220, 190, 130, 200), Size: 0.2, Growth: 3, Life: 1, Gravity: -2, Drag: 1.2, Spread: 1.2, PerMeter: 3},
+	ParticleSpark: {Color: rl.NewColor(255, 200, 60, 255), Size: 0.06, Growth: 0.5, Life: 0.5, Gravity: -9.8, Drag: 0.5, Spread: 5},
+	ParticleSmoke: {Color: rl.NewColor(90, 90, 90, 150), Size: 0.15, Growth: 4, Life: 1.5, Gravity: 0.5, Drag: 1, Spread: 0.3},
+}
+
+// particle is one live or free slot in a particlePool.
+type particle struct {
+	Kind     ParticleKind
+	Position rl.Vector3
+	Velocity rl.Vector3
+	Age      float32
+	Alive    bool
+}
+
+// particlePool holds a fixed number of particles and reuses dead slots.
+type particlePool struct {
+	Particles []particle
+	free      []int // indices of dead slots
+}
+
+func newParticlePool(size int) *particlePool {
+	pool := &particlePool{Particles: make([]particle, size), free: make([]int, size)}
+	for i := range pool.free {
+		pool.free[i] = size - 1 - i
+	}
+	return pool
+}
+
+// Spawn starts a particle, reporting false when the pool is full.
+func (p *particlePool) Spawn(kind ParticleKind, position, velocity rl.Vector3) bool {
+	if len(p.free) == 0 {
+		return false
+	}
+	i := p.free[len(p.free)-1]
+	p.free = p.free[:len(p.free)-1]
+	p.Particles[i] = particle{Kind: kind, Position: position, Velocity: velocity, Alive: true}
+	return true
+}
+
+// Update ages and moves every live particle and frees the ones that expired.
+func (p *particlePool) Update(dt float32) {
+	for i := range p.Particles {
+		q := &p.Particles[i]
+		if !q.Alive {
+			continue
+		}
+		def := particleDefs[q.Kind]
+		q.Age += dt
+		if q.Age >= def.Life || q.Position.Y < 0 {
+			q.Alive = false
+			p.free = append(p.free, i)
+			continue
+		}
+		q.Velocity.Y += def.Gravity * dt
+		q.Velocity = rl.Vector3Scale(q.Velocity, float32(math.Max(0, float64(1-def.Drag*dt))))
+		q.Position = rl.Vector3Add(q.Position, rl.Vector3Scale(q.Velocity, dt))
+	}
+}
+
+// Alive returns the number of live particles.
+func (p *particlePool) Alive() int {
+	return len(p.Particles) - len(p.free)
+}
+
+// emitter spawns particles of one kind at a varying rate, carrying fractions
+// of a particle over to the next frame so low rates still emit.
+type emitter struct {
+	Kind  ParticleKind
+	carry float32
+}
+
+// Emit spawns rate*dt particles (on average) at position with the given base velocity.
+func (e *emitter) Emit(pool *particlePool, rng *rand.Rand, rate, dt float32, position, velocity rl.Vector3) {
+	e.carry += rate * dt
+	for ; e.carry >= 1; e.carry-- {
+		pool.Spawn(e.Kind, jitter(rng, position, 0.2), jitter(rng, velocity, particleDefs[e.Kind].Spread))
+	}
+}
+
+// burst spawns count particles at once, flying outward from position.
+func burst(pool *particlePool, rng *rand.Rand, kind ParticleKind, count int, position rl.Vector3) {
+	for i := 0; i < count; i++ {
+		pool.Spawn(kind, position, jitter(rng, rl.Vector3{Y: 2}, particleDefs[kind].Spread))
+	}
+}
+
+// jitter offsets v by up to amount in each direction.
+func jitter(rng *rand.Rand, v rl.Vector3, amount float32) rl.Vector3 {
+	return rl.Vector3{
+		X: v.X + (rng.Float32()*2-1)*amount,
+		Y: v.Y + (rng.Float32()*2-1)*amount,
+		Z: v.Z + (rng.Float32()*2-1)*amount,
+	}
+}
+
+// surfaceParticle returns the particle kicked up by the wheels on a chunk, if any.
+func surfaceParticle(chunk *Chunk) (ParticleKind, bool) {
+	switch {
+	case chunk == nil:
+		return 0, false
+	case chunk.Type == Desert:
+		return ParticleSand, true
+	case chunk.RoadType == RoadIce:
+		return ParticleSnow, true
+	case chunk.RoadType == RoadDirt:
+		return ParticleDust, true
+	}
+	return 0, false
+}
+
+// wheelEmissionRate returns the particles per second each rear wheel kicks up
+// on a chunk at the given speed (m/s), and their kind.
+func wheelEmissionRate(chunk *Chunk, speed float32) (ParticleKind, float32) {
+	kind, ok := surfaceParticle(chunk)
+	if !ok {
+		return 0, 0
+	}
+	return kind, particleDefs[kind].PerMeter * speed / 2
+}
+
+var (
+	particles    = newParticlePool(MAX_PARTICLES)
+	particleRand = rand.New(rand.NewSource(1))
+	// One emitter per rear wheel for surface effects, and the exhaust.
+	wheelEmitters  [2]emitter
+	exhaustEmitter = emitter{Kind: ParticleSmoke}
+)
+
+// updateParticles emits particles from the car and advances the simulation.
+func updateParticles() {
+	dt := rl.GetFrameTime()
+	forward := carForward()
+	right := rl.Vector3{X: -forward.Z, Z: forward.X}
+	rear := rl.Vector3Add(car.position, rl.Vector3Scale(forward, -1))
+	speed := float32(math.Abs(float64(car.speed)))
+
+	if kind, rate := wheelEmissionRate(chunks[getChunkCoord(car.position)], speed); rate > 0 {
+		// Thrown back and up, faster the faster the car goes.
+		velocity := rl.Vector3Add(rl.Vector3Scale(forward, -0.2*car.speed), rl.Vector3{Y: 1 + speed*0.05})
+		for i, side := range []float32{-0.45, 0.45} {
+			wheelEmitters[i].Kind = kind
+			position := rl.Vector3Add(rear, rl.Vector3Scale(right, side))
+			position.Y = 0.3
+			wheelEmitters[i].Emit(particles, particleRand, rate, dt, position, velocity)
+		}
+	}
+
+	// A steady idle puff, thicker under throttle.
+	rate := float32(4)
+	if rl.IsKeyDown(rl.KeyUp) && car.fuel > 0 {
+		rate += 12
+	}
+	exhaust := rl.Vector3Add(rl.Vector3Add(rear, rl.Vector3Scale(right, 0.3)), rl.Vector3{Y: 0.2})
+	exhaustEmitter.Emit(particles, particleRand, rate, dt, exhaust, rl.Vector3Scale(forward, -1))
+
+	particles.Update(dt)
+}
+
+// emitCollisionSparks throws sparks from the front of the car, more for harder hits.
+func emitCollisionSparks(speed float32) {
+	front := rl.Vector3Add(car.position, rl.Vector3Add(carForward(), rl.Vector3{Y: 0.3}))
+	burst(particles, particleRand, ParticleSpark, 10+int(speed*2), front)
+}
+
+// drawParticles draws live particles as small cubes that grow and fade with age.
+func drawParticles() {
+	for _, p := range particles.Particles {
+		if !p.Alive {
+			continue
+		}
+		def := particleDefs[p.Kind]
+		t := p.Age / def.Life
+		size := def.Size * (1 + (def.Growth-1)*t)
+		renderer.DrawCube(p.Position, rl.Vector3{X: size, Y: size, Z: size}, rl.Fade(def.Color, float32(def.Color.A)/255*(1-t)))
+	}
+}
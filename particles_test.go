@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestParticlePoolExhaustion(t *testing.T) {
+	pool := newParticlePool(3)
+	for i := 0; i < 3; i++ {
+		if !pool.Spawn(ParticleDust, rl.Vector3{Y: 1}, rl.Vector3{}) {
+			t.Fatalf("spawn %d refused with free slots left", i)
+		}
+	}
+	if pool.Spawn(ParticleDust, rl.Vector3{Y: 1}, rl.Vector3{}) {
+		t.Fatal("spawn accepted with the pool full")
+	}
+	if got := pool.Alive(); got != 3 {
+		t.Fatalf("Alive() = %d, want 3", got)
+	}
+}
+
+func TestParticlePoolReusesExpiredSlots(t *testing.T) {
+	pool := newParticlePool(2)
+	pool.Spawn(ParticleSpark, rl.Vector3{Y: 5}, rl.Vector3{})
+	pool.Spawn(ParticleSmoke, rl.Vector3{Y: 5}, rl.Vector3{})
+
+	// Sparks live shorter than smoke, so only the spark's slot frees up.
+	pool.Update(particleDefs[ParticleSpark].Life)
+	if got := pool.Alive(); got != 1 {
+		t.Fatalf("Alive() after the spark expired = %d, want 1", got)
+	}
+	if !pool.Spawn(ParticleDust, rl.Vector3{Y: 5}, rl.Vector3{}) {
+		t.Fatal("expired slot was not reused")
+	}
+	if pool.Spawn(ParticleDust, rl.Vector3{Y: 5}, rl.Vector3{}) {
+		t.Fatal("spawn accepted with the pool full again")
+	}
+	kinds := map[ParticleKind]bool{}
+	for _, p := range pool.Particles {
+		kinds[p.Kind] = p.Alive
+	}
+	if !kinds[ParticleDust] || !kinds[ParticleSmoke] || kinds[ParticleSpark] {
+		t.Fatalf("live kinds = %v, want dust and smoke", kinds)
+	}
+}
+
+func TestParticleLifetime(t *testing.T) {
+	for kind, def := range particleDefs {
+		pool := newParticlePool(1)
+		// High enough that gravity can't take it below the ground first.
+		pool.Spawn(ParticleKind(kind), rl.Vector3{Y: 100}, rl.Vector3{})
+		const dt = 0.01
+		steps := 0
+		for pool.Alive() > 0 && steps < 1000 {
+			pool.Update(dt)
+			steps++
+		}
+		if lived := float32(steps) * dt; lived < def.Life-dt || lived > def.Life+dt {
+			t.Errorf("kind %d lived %.2fs, want %.2fs", kind, lived, def.Life)
+		}
+	}
+}
+
+func TestParticleExpiresBelowGround(t *testing.T) {
+	pool := newParticlePool(1)
+	pool.Spawn(ParticleSpark, rl.Vector3{Y: 0.01}, rl.Vector3{Y: -5})
+	pool.Update(0.01)
+	pool.Update(0.01)
+	if pool.Alive() != 0 {
+		t.Fatal("particle below the ground is still alive")
+	}
+}
+
+func TestEmitterRate(t *testing.T) {
+	pool := newParticlePool(MAX_PARTICLES)
+	e := emitter{Kind: ParticleSmoke}
+	rng := rand.New(rand.NewSource(1))
+	// 2.5 per second over 60 frames of 1/60 s: fractions carry over to later frames.
+	for i := 0; i < 60; i++ {
+		e.Emit(pool, rng, 2.5, 1.0/60, rl.Vector3{Y: 1}, rl.Vector3{})
+	}
+	if got := pool.Alive(); got != 2 {
+		t.Fatalf("emitted %d particles in one second at 2.5/s, want 2", got)
+	}
+}
+
+func TestWheelEmissionRate(t *testing.T) {
+	desert := &Chunk{Type: Desert, RoadType: RoadNormal}
+	snow := &Chunk{Type: Snow, RoadType: RoadIce}
+	forest := &Chunk{Type: Forest, RoadType: RoadDirt}
+	city := &Chunk{Type: City, RoadType: RoadNormal}
+
+	for _, c := range []struct {
+		chunk *Chunk
+		kind  ParticleKind
+	}{{desert, ParticleSand}, {snow, ParticleSnow}, {forest, ParticleDust}} {
+		kind, slow := wheelEmissionRate(c.chunk, 10)
+		_, fast := wheelEmissionRate(c.chunk, 20)
+		if kind != c.kind {
+			t.Errorf("chunk type %d emits kind %d, want %d", c.chunk.Type, kind, c.kind)
+		}
+		if slow <= 0 || fast != 2*slow {
+			t.Errorf("chunk type %d: rate %.1f at 10 m/s and %.1f at 20 m/s, want it to double", c.chunk.Type, slow, fast)
+		}
+		if _, still := wheelEmissionRate(c.chunk, 0); still != 0 {
+			t.Errorf("chunk type %d emits %.1f/s standing still", c.chunk.Type, still)
+		}
+	}
+
+	// Snow throws more per meter than sand.
+	_, sand := wheelEmissionRate(desert, 10)
+	_, flakes := wheelEmissionRate(snow, 10)
+	if flakes <= sand {
+		t.Errorf("snow rate %.1f not above sand rate %.1f", flakes, sand)
+	}
+
+	for _, chunk := range []*Chunk{city, nil} {
+		if _, rate := wheelEmissionRate(chunk, 20); rate != 0 {
+			t.Errorf("paved or missing chunk %v emits %.1f/s", chunk, rate)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Sidewalks run along both sides of the main roads of City and Commercial chunks.
+const (
+	SIDEWALK_WIDTH  float32 = 1.5
+	SIDEWALK_OFFSET         = ROAD_WIDTH/2 + SIDEWALK_WIDTH/2 // from road centerline to sidewalk centerline
+)
+
+// Walking and running speeds in m/s.
+const (
+	WALK_SPEED float32 = 1.4
+	FLEE_SPEED float32 = 4.5
+)
+
+// Pedestrians scatter when a moving car comes within SCARE_RADIUS, or when it honks within HONK_RADIUS.
+const (
+	SCARE_RADIUS float32 = 10
+	HONK_RADIUS  float32 = 25
+)
+
+// Pedestrians spawned per chunk, by chunk type.
+var pedestrianDensity = map[int]int{City: 6, Commercial: 3}
+
+var shirtColors = []rl.Color{rl.Red, rl.Blue, rl.Yellow, rl.Purple, rl.Orange, rl.Lime}
+
+// pedestrianState is what a pedestrian is doing.
+type pedestrianState int
+
+const (
+	PedWalking   pedestrianState = iota // along a sidewalk
+	PedFleeing                          // running from the car
+	PedReturning                        // heading back to the nearest sidewalk
+)
+
+// pedestrian is a person walking the sidewalks of the chunk they spawned in.
+type pedestrian struct {
+	Position rl.Vector3
+	Home     Coord
+	State    pedestrianState
+	Dir      int // index into trafficDirs while walking
+	Heading  rl.Vector3
+	Timer    float32 // seconds of fleeing left
+	Color    int
+	// Last sidewalk crossing point decided, so it isn't decided again right after turning.
+	lastCorner rl.Vector3
+}
+
+var (
+	pedestrians []pedestrian
+	// Shared model; tinted with the shirt color unless it comes from the asset manifest.
+	pedestrianModel  rl.Model
+	pedestrianTinted bool
+)
+
+// initPedestrians loads the pedestrian model.
+func initPedestrians() {
+	var asset bool
+	pedestrianModel, asset = loadModelOrPrimitive(manifest.Characters["pedestrian"], func() rl.Mesh { return rl.GenMeshCylinder(0.25, 1.7, 8) })
+	pedestrianTinted = !asset
+}
+
+// chunkSidewalks returns the sidewalk strips of a chunk.
+func chunkSidewalks(chunk *Chunk) []roadStrip {
+	if _, ok := pedestrianDensity[chunk.Type]; !ok {
+		return nil
+	}
+	cx := float32(chunk.Coord.X)*CHUNK_SIZE + CHUNK_SIZE/2
+	cz := float32(chunk.Coord.Y)*CHUNK_SIZE + CHUNK_SIZE/2
+	return []roadStrip{
+		{X: cx, Z: cz - SIDEWALK_OFFSET, Width: CHUNK_SIZE, Length: SIDEWALK_WIDTH},
+		{X: cx, Z: cz + SIDEWALK_OFFSET, Width: CHUNK_SIZE, Length: SIDEWALK_WIDTH},
+		{X: cx - SIDEWALK_OFFSET, Z: cz, Width: SIDEWALK_WIDTH, Length: CHUNK_SIZE},
+		{X: cx + SIDEWALK_OFFSET, Z: cz, Width: SIDEWALK_WIDTH, Length: CHUNK_SIZE},
+	}
+}
+
+// sidewalkAt reports whether (x, z) is on a sidewalk of the given chunk.
+func sidewalkAt(home Coord, x, z float32) bool {
+	chunk := chunks[home]
+	if chunk == nil {
+		return false
+	}
+	for _, walk := range chunkSidewalks(chunk) {
+		if float32(math.Abs(float64(x-walk.X))) <= walk.Width/2 && float32(math.Abs(float64(z-walk.Z))) <= walk.Length/2 {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnChunkPedestrians places pedestrians on the sidewalks of a newly built chunk.
+func spawnChunkPedestrians(chunk *Chunk) {
+	walks := chunkSidewalks(chunk)
+	for k := 0; k < pedestrianDensity[chunk.Type] && len(walks) > 0; k++ {
+		walk := walks[rand.Intn(len(walks))]
+		p := pedestrian{Home: chunk.Coord, Color: rand.Intn(len(shirtColors))}
+		if walk.Width == CHUNK_SIZE {
+			p.Dir = 2 * rand.Intn(2)
+			p.Position = rl.Vector3{X: walk.X + (rand.Float32()-0.5)*walk.Width*0.9, Z: walk.Z}
+		} else {
+			p.Dir = 1 + 2*rand.Intn(2)
+			p.Position = rl.Vector3{X: walk.X, Z: walk.Z + (rand.Float32()-0.5)*walk.Length*0.9}
+		}
+		p.Heading = trafficDirs[p.Dir]
+		pedestrians = append(pedestrians, p)
+	}
+}
+
+// despawnChunkPedestrians removes the pedestrians belonging to an unloaded chunk.
+func despawnChunkPedestrians(coord Coord) {
+	kept := pedestrians[:0]
+	for _, p := range pedestrians {
+		if p.Home != coord {
+			kept = append(kept, p)
+		}
+	}
+	pedestrians = kept
+}
+
+// updatePedestrians handles the horn and moves every pedestrian.
+func updatePedestrians() {
+	dt := rl.GetFrameTime()
+	honked := rl.IsKeyPressed(rl.KeyH)
+	if honked {
+		notify("Honk!")
+	}
+	speed := float32(math.Abs(float64(car.speed)))
+	for i := range pedestrians {
+		p := &pedestrians[i]
+		distance := rl.Vector3Distance(p.Position, car.position)
+		if (speed > 3 && distance < SCARE_RADIUS) || (honked && distance < HONK_RADIUS) {
+			scarePedestrian(p)
+		}
+		switch p.State {
+		case PedWalking:
+			walkPedestrian(p, dt)
+		case PedFleeing:
+			next := rl.Vector3Add(p.Position, rl.Vector3Scale(p.Heading, FLEE_SPEED*dt))
+			if !insideCollider(next) {
+				p.Position = next
+			}
+			p.Timer -= dt
+			if p.Timer <= 0 {
+				p.State = PedReturning
+			}
+		case PedReturning:
+			returnPedestrian(p, dt)
+		}
+	}
+}
+
+// scarePedestrian sends a pedestrian running away from the car.
+func scarePedestrian(p *pedestrian) {
+	away := rl.Vector3Subtract(p.Position, car.position)
+	away.Y = 0
+	// Run off at an angle so a crowd spreads out instead of running in a line.
+	side := rl.Vector3{X: -away.Z, Z: away.X}
+	away = rl.Vector3Add(away, rl.Vector3Scale(side, rand.Float32()-0.5))
+	if rl.Vector3Length(away) < 0.01 {
+		away = rl.Vector3{X: 1}
+	}
+	if p.State != PedFleeing {
+		p.Heading = rl.Vector3Normalize(away)
+	}
+	p.State = PedFleeing
+	p.Timer = 2
+}
+
+// walkPedestrian moves a pedestrian along the sidewalk, choosing at each
+// sidewalk crossing whether to carry on (crossing the road ahead) or turn.
+func walkPedestrian(p *pedestrian, dt float32) {
+	d := trafficDirs[p.Dir]
+	sign := alongAxis(d, p.Dir)
+	before := alongAxis(p.Position, p.Dir)
+	p.Position = rl.Vector3Add(p.Position, rl.Vector3Scale(d, WALK_SPEED*dt))
+	after := alongAxis(p.Position, p.Dir)
+
+	if chunk := chunks[p.Home]; chunk != nil {
+		for _, walk := range chunkSidewalks(chunk) {
+			// Sidewalks across the walking direction.
+			crossing := walk.X
+			if p.Dir%2 == 0 {
+				if walk.Width == CHUNK_SIZE {
+					continue
+				}
+			} else {
+				if walk.Length == CHUNK_SIZE {
+					continue
+				}
+				crossing = walk.Z
+			}
+			if sign*(crossing-before) < 0 || sign*(crossing-after) > 0 {
+				continue
+			}
+			corner := p.Position
+			if p.Dir%2 == 0 {
+				corner.X = crossing
+			} else {
+				corner.Z = crossing
+			}
+			if corner == p.lastCorner {
+				continue
+			}
+			p.lastCorner = corner
+			var options []int
+			for _, dir := range []int{p.Dir, p.Dir, (p.Dir + 1) % 4, (p.Dir + 3) % 4} {
+				probe := rl.Vector3Add(corner, trafficDirs[dir])
+				if sidewalkAt(p.Home, probe.X, probe.Z) {
+					options = append(options, dir)
+				}
+			}
+			if len(options) > 0 {
+				if dir := options[rand.Intn(len(options))]; dir != p.Dir {
+					p.Position, p.Dir = corner, dir
+				}
+			}
+			break
+		}
+	}
+
+	// Turn back at the end of the sidewalk.
+	ahead := rl.Vector3Add(p.Position, rl.Vector3Scale(trafficDirs[p.Dir], 0.8))
+	if !sidewalkAt(p.Home, ahead.X, ahead.Z) {
+		p.Dir = (p.Dir + 2) % 4
+	}
+	p.Heading = trafficDirs[p.Dir]
+}
+
+// returnPedestrian walks a pedestrian back to the nearest point of a sidewalk
+// of their chunk and sets them walking along it.
+func returnPedestrian(p *pedestrian, dt float32) {
+	chunk := chunks[p.Home]
+	if chunk == nil {
+		return
+	}
+	var target rl.Vector3
+	best := float32(math.MaxFloat32)
+	horizontal := false
+	for _, walk := range chunkSidewalks(chunk) {
+		point := rl.Vector3{
+			X: rl.Clamp(p.Position.X, walk.X-walk.Width/2, walk.X+walk.Width/2),
+			Z: rl.Clamp(p.Position.Z, walk.Z-walk.Length/2, walk.Z+walk.Length/2),
+		}
+		if walk.Width == CHUNK_SIZE {
+			point.Z = walk.Z
+		} else {
+			point.X = walk.X
+		}
+		if distance := rl.Vector3Distance(point, p.Position); distance < best {
+			best, target, horizontal = distance, point, walk.Width == CHUNK_SIZE
+		}
+	}
+	step := WALK_SPEED * dt
+	if best <= step {
+		p.Position = target
+		p.State = PedWalking
+		p.Dir = 2 * rand.Intn(2)
+		if !horizontal {
+			p.Dir++
+		}
+		p.Heading = trafficDirs[p.Dir]
+		return
+	}
+	p.Heading = rl.Vector3Normalize(rl.Vector3Subtract(target, p.Position))
+	next := rl.Vector3Add(p.Position, rl.Vector3Scale(p.Heading, step))
+	if !insideCollider(next) {
+		p.Position = next
+	}
+}
+
+// insideCollider reports whether a point is inside a prop's collider.
+func insideCollider(pos rl.Vector3) bool {
+	for _, box := range nearbyColliders(pos, 1) {
+		if pos.X > box.Min.X && pos.X < box.Max.X && pos.Z > box.Min.Z && pos.Z < box.Max.Z {
+			return true
+		}
+	}
+	return false
+}
+
+// drawPedestrians draws the pedestrians, one batch per shirt color.
+func drawPedestrians() {
+	batches := make([][]rl.Matrix, len(shirtColors))
+	for _, p := range pedestrians {
+		yaw := math.Atan2(float64(p.Heading.Z), float64(p.Heading.X))
+		transform := rl.MatrixMultiply(rl.MatrixRotateY(float32(math.Pi/2-yaw)), rl.MatrixTranslate(p.Position.X, 0, p.Position.Z))
+		batches[p.Color] = append(batches[p.Color], transform)
+	}
+	for i, transforms := range batches {
+		tint := rl.White
+		if pedestrianTinted {
+			tint = shirtColors[i]
+		}
+		renderer.DrawModel(pedestrianModel, transforms, tint)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Directory screenshots are written to, relative to the working directory.
+const SCREENSHOT_DIR = "screenshots"
+
+// How far the photo camera may wander from the car, in meters.
+const PHOTO_RADIUS float32 = 30
+
+// Post-processing for photo mode: exposure in stops and a color matrix.
+const photoFS = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float exposure;
+uniform vec3 colorMatrix[3];
+out vec4 finalColor;
+void main() {
+    vec3 c = texture(texture0, fragTexCoord).rgb*exp2(exposure);
+    c = vec3(dot(colorMatrix[0], c), dot(colorMatrix[1], c), dot(colorMatrix[2], c));
+    finalColor = vec4(clamp(c, 0.0, 1.0), 1.0);
+}
+`
+
+// photoFilter is a color filter selectable in photo mode; each row mixes the
+// input RGB into one output channel.
+type photoFilter struct {
+	Name   string
+	Matrix [9]float32
+}
+
+var photoFilters = []photoFilter{
+	{"None", [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1}},
+	{"Black & White", [9]float32{0.299, 0.587, 0.114, 0.299, 0.587, 0.114, 0.299, 0.587, 0.114}},
+	{"Sepia", [9]float32{0.393, 0.769, 0.189, 0.349, 0.686, 0.168, 0.272, 0.534, 0.131}},
+	{"Warm", [9]float32{1.1, 0, 0, 0, 1, 0, 0, 0, 0.85}},
+	{"Cool", [9]float32{0.9, 0, 0, 0, 1, 0, 0, 0, 1.15}},
+}
+
+var (
+	// Set by the screenshot key; the frame is saved once it has been drawn.
+	screenshotWanted bool
+
+	// Gameplay camera to restore when leaving photo mode.
+	photoSavedCamera rl.Camera3D
+	// Free camera look angles in radians.
+	photoYaw, photoPitch float32
+	photoExposure        float32 // stops
+	photoFilterIndex     int
+	photoShowHelp        = true
+
+	photoShader                   rl.Shader
+	photoExposureLoc, photoMatLoc int32
+	photoTarget                   rl.RenderTexture2D
+)
+
+// updateScreenshot handles the screenshot key.
+func updateScreenshot() {
+	if rl.IsKeyPressed(rl.KeyF12) {
+		screenshotWanted = true
+	}
+}
+
+// captureScreenshot saves the frame drawn so far if a screenshot was requested.
+func captureScreenshot() {
+	if !screenshotWanted {
+		return
+	}
+	screenshotWanted = false
+	if err := os.MkdirAll(SCREENSHOT_DIR, 0o755); err != nil {
+		rl.TraceLog(rl.LogWarning, "screenshot failed: %v", err)
+		return
+	}
+	now := time.Now()
+	name := fmt.Sprintf("drive3d_%s_%03d.png", now.Format("20060102_150405"), now.Nanosecond()/1e6)
+	path := filepath.Join(SCREENSHOT_DIR, name)
+	image := rl.LoadImageFromScreen()
+	ok := rl.ExportImage(*image, path)
+	rl.UnloadImage(image)
+	if !ok {
+		rl.TraceLog(rl.LogWarning, "screenshot failed: could not write %s", path)
+		return
+	}
+	notify("Saved " + path)
+}
+
+// openPhotoMode pauses the game and frees the camera.
+func openPhotoMode() {
+	if photoShader.ID == 0 {
+		photoShader = rl.LoadShaderFromMemory("", photoFS)
+		photoExposureLoc = rl.GetShaderLocation(photoShader, "exposure")
+		photoMatLoc = rl.GetShaderLocation(photoShader, "colorMatrix")
+	}
+	photoSavedCamera = camera
+	dir := rl.Vector3Normalize(rl.Vector3Subtract(camera.Target, camera.Position))
+	photoYaw = float32(math.Atan2(float64(dir.Z), float64(dir.X)))
+	photoPitch = rl.Clamp(float32(math.Asin(float64(dir.Y))), -1.5, 1.5)
+	camera.Up = rl.Vector3{Y: 1}
+	photoExposure = 0
+	photoFilterIndex = 0
+	currentState = PhotoMode
+}
+
+// closePhotoMode restores the gameplay camera and resumes play.
+func closePhotoMode() {
+	camera = photoSavedCamera
+	currentState = Playing
+}
+
+// updatePhotoMode flies the camera around the car and adjusts the look.
+func updatePhotoMode() {
+	if rl.IsKeyPressed(rl.KeyP) {
+		closePhotoMode()
+		return
+	}
+	updateScreenshot()
+	dt := rl.GetFrameTime()
+
+	// Right mouse drag looks around, WASD moves, Q/E lower and raise.
+	if rl.IsMouseButtonDown(rl.MouseRightButton) {
+		delta := rl.GetMouseDelta()
+		photoYaw += delta.X * 0.005
+		photoPitch = rl.Clamp(photoPitch-delta.Y*0.005, -1.5, 1.5)
+	}
+	cosPitch := float32(math.Cos(float64(photoPitch)))
+	look := rl.Vector3{
+		X: float32(math.Cos(float64(photoYaw))) * cosPitch,
+		Y: float32(math.Sin(float64(photoPitch))),
+		Z: float32(math.Sin(float64(photoYaw))) * cosPitch,
+	}
+	flat := rl.Vector3{X: float32(math.Cos(float64(photoYaw))), Z: float32(math.Sin(float64(photoYaw)))}
+	right := rl.Vector3{X: -flat.Z, Z: flat.X}
+	var move rl.Vector3
+	if rl.IsKeyDown(rl.KeyW) {
+		move = rl.Vector3Add(move, flat)
+	}
+	if rl.IsKeyDown(rl.KeyS) {
+		move = rl.Vector3Subtract(move, flat)
+	}
+	if rl.IsKeyDown(rl.KeyD) {
+		move = rl.Vector3Add(move, right)
+	}
+	if rl.IsKeyDown(rl.KeyA) {
+		move = rl.Vector3Subtract(move, right)
+	}
+	if rl.IsKeyDown(rl.KeyE) {
+		move.Y++
+	}
+	if rl.IsKeyDown(rl.KeyQ) {
+		move.Y--
+	}
+	position := rl.Vector3Add(camera.Position, rl.Vector3Scale(move, 10*dt))
+	// Stay above the ground and within PHOTO_RADIUS of the car.
+	position.Y = float32(math.Max(0.3, float64(position.Y)))
+	offset := rl.Vector3Subtract(position, car.position)
+	if rl.Vector3Length(offset) > PHOTO_RADIUS {
+		position = rl.Vector3Add(car.position, rl.Vector3Scale(rl.Vector3Normalize(offset), PHOTO_RADIUS))
+	}
+	camera.Position = position
+	camera.Target = rl.Vector3Add(position, look)
+
+	camera.Fovy = rl.Clamp(camera.Fovy-rl.GetMouseWheelMove()*2, 10, 100)
+	if rl.IsKeyDown(rl.KeyEqual) {
+		photoExposure = rl.Clamp(photoExposure+dt, -2, 2)
+	}
+	if rl.IsKeyDown(rl.KeyMinus) {
+		photoExposure = rl.Clamp(photoExposure-dt, -2, 2)
+	}
+	if rl.IsKeyPressed(rl.KeyF) {
+		photoFilterIndex = (photoFilterIndex + 1) % len(photoFilters)
+	}
+	if rl.IsKeyPressed(rl.KeyH) {
+		photoShowHelp = !photoShowHelp
+	}
+}
+
+// drawPhotoMode draws the scene through the exposure and color filter, without the HUD.
+func drawPhotoMode() {
+	width, height := int32(rl.GetRenderWidth()), int32(rl.GetRenderHeight())
+	if photoTarget.Texture.Width != width || photoTarget.Texture.Height != height {
+		if photoTarget.ID != 0 {
+			rl.UnloadRenderTexture(photoTarget)
+		}
+		photoTarget = rl.LoadRenderTexture(width, height)
+	}
+	rl.BeginTextureMode(photoTarget)
+	drawScene()
+	rl.EndTextureMode()
+
+	filter := photoFilters[photoFilterIndex]
+	rl.SetShaderValue(photoShader, photoExposureLoc, []float32{photoExposure}, rl.ShaderUniformFloat)
+	rl.SetShaderValueV(photoShader, photoMatLoc, filter.Matrix[:], rl.ShaderUniformVec3, 3)
+	renderer.ClearBackground(rl.Black)
+	rl.BeginShaderMode(photoShader)
+	// Render textures are stored upside down.
+	source := rl.Rectangle{Width: float32(width), Height: -float32(height)}
+	screenW, screenH := renderer.ScreenSize()
+	dest := rl.Rectangle{Width: screenW, Height: screenH}
+	renderer.DrawTexture(photoTarget.Texture, source, dest, rl.White)
+	rl.EndShaderMode()
+	captureScreenshot()
+
+	if photoShowHelp {
+		lines := []string{
+			fmt.Sprintf("PHOTO MODE   Filter: %s   Exposure: %+.1f   FOV: %.0f", filter.Name, photoExposure, camera.Fovy),
+			"WASD/QE move, right drag look, wheel FOV, -/+ exposure, F filter",
+			"F12 screenshot, H hide help, P resume",
+		}
+		for i, line := range lines {
+			pos := uiRect(AnchorBottomLeft, 10, float32(10+25*(len(lines)-1-i)), 600, 20)
+			drawUIText(line, pos.X, pos.Y, 20, rl.White)
+		}
+	}
+	drawNotification()
+}
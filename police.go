@@ -0,0 +1,368 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Highest wanted level; one police car gives chase per level.
+const MAX_WANTED = 5
+
+// Crashes faster than this (m/s) raise the wanted level.
+const CRASH_WANTED_SPEED float32 = 8
+
+// Police see the car within this range unless something is in the way, and
+// give up after ESCAPE_TIME seconds without seeing it.
+const (
+	POLICE_SIGHT_RANGE float32 = 80
+	ESCAPE_TIME        float32 = 8
+)
+
+// Police cars arrive this far from the car and are dropped beyond POLICE_DESPAWN_RANGE.
+const (
+	POLICE_SPAWN_MIN     float32 = 60
+	POLICE_SPAWN_MAX     float32 = 110
+	POLICE_DESPAWN_RANGE float32 = 200
+)
+
+// Stopped with police within ARREST_RANGE for ARREST_TIME seconds is an arrest.
+const (
+	ARREST_RANGE float32 = 5
+	ARREST_TIME  float32 = 2
+)
+
+// Roadblocks go up in chunks ahead from this wanted level.
+const ROADBLOCK_WANTED = 2
+
+// Chance that a Highway, City or Commercial chunk has a speed camera.
+const SPEED_TRAP_CHANCE float32 = 0.35
+
+// Speed limits in m/s for chunk types with speed cameras.
+var speedLimits = map[int]float32{Highway: 100 / 3.6, City: 50 / 3.6, Commercial: 50 / 3.6}
+
+// policeCar chases the player straight across the map, steering around props.
+type policeCar struct {
+	Position rl.Vector3
+	Yaw      float32
+	Speed    float32
+}
+
+// roadblock is a barrier segment across a road in a chunk ahead of the car.
+type roadblock struct {
+	Chunk Coord
+	Box   rl.BoundingBox
+}
+
+var (
+	wanted     int
+	policeCars []policeCar
+	roadblocks []roadblock
+	// Seconds since any police car saw the car, and spent stopped next to police.
+	hideTimer, arrestTimer float32
+	policeSpawnTimer       float32
+	// The chunk whose speed camera last flashed, so it only flashes once per pass.
+	flashedTrap   Coord
+	trapFlashed   bool
+	trapFlashTime float32
+)
+
+// chunkSpeedTrap places the speed camera beside a main road of a newly built chunk, if it has one.
+func chunkSpeedTrap(chunk *Chunk) (rl.Vector3, bool) {
+	if _, ok := speedLimits[chunk.Type]; !ok || chunk.Coord == (Coord{0, 0}) {
+		return rl.Vector3{}, false
+	}
+	r := seededRandom("trap", chunk.Coord.X, chunk.Coord.Y)
+	if r.Float32() >= SPEED_TRAP_CHANCE {
+		return rl.Vector3{}, false
+	}
+	dir := r.Intn(4)
+	position := rl.Vector3Add(chunkCenter(chunk.Coord), rl.Vector3Scale(trafficDirs[dir], CHUNK_SIZE/4))
+	return rl.Vector3Add(position, rl.Vector3Scale(trafficDirs[(dir+1)%4], ROAD_WIDTH/2+1)), true
+}
+
+// raiseWanted adds a wanted level and tells the player why.
+func raiseWanted(reason string) {
+	if wanted < MAX_WANTED {
+		wanted++
+	}
+	hideTimer = 0
+	notify(fmt.Sprintf("%s! Wanted level %d", reason, wanted))
+}
+
+// reportCrash is called when the car hits something at speed (m/s).
+func reportCrash(speed float32) {
+	if speed > CRASH_WANTED_SPEED {
+		raiseWanted("Reckless driving")
+	}
+}
+
+// policeNear reports whether a police car is within distance of pos.
+func policeNear(pos rl.Vector3, distance float32) bool {
+	for _, p := range policeCars {
+		if rl.Vector3Distance(p.Position, pos) < distance {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnChunkRoadblock may block the road into a newly built chunk ahead of a wanted car.
+func spawnChunkRoadblock(chunk *Chunk) {
+	if wanted < ROADBLOCK_WANTED || rand.Float32() < 0.5 {
+		return
+	}
+	center := chunkCenter(chunk.Coord)
+	to := rl.Vector3Subtract(center, car.position)
+	if rl.Vector3DotProduct(to, carForward()) <= 0 {
+		return
+	}
+	// Across the arm of the main road that faces the car, made of segments so
+	// each fits the round collision check.
+	const segment = 1.8
+	size := rl.Vector3{X: segment, Y: 1.2, Z: segment}
+	along := rl.Vector3{X: float32(math.Copysign(1, float64(to.X)))}
+	if math.Abs(float64(to.Z)) > math.Abs(float64(to.X)) {
+		along = rl.Vector3{Z: float32(math.Copysign(1, float64(to.Z)))}
+	}
+	across := rl.Vector3{X: -along.Z, Z: along.X}
+	base := rl.Vector3Subtract(center, rl.Vector3Scale(along, CHUNK_SIZE/2-6))
+	for _, offset := range []float32{-1.5, -0.5, 0.5, 1.5} {
+		at := rl.Vector3Add(base, rl.Vector3Scale(across, offset*segment))
+		box := rl.BoundingBox{
+			Min: rl.Vector3{X: at.X - size.X/2, Z: at.Z - size.Z/2},
+			Max: rl.Vector3{X: at.X + size.X/2, Y: size.Y, Z: at.Z + size.Z/2},
+		}
+		roadblocks = append(roadblocks, roadblock{Chunk: chunk.Coord, Box: box})
+		chunk.Colliders = append(chunk.Colliders, box)
+	}
+}
+
+// despawnChunkRoadblocks forgets the roadblocks of an unloaded chunk.
+func despawnChunkRoadblocks(coord Coord) {
+	kept := roadblocks[:0]
+	for _, rb := range roadblocks {
+		if rb.Chunk != coord {
+			kept = append(kept, rb)
+		}
+	}
+	roadblocks = kept
+}
+
+// clearPursuit ends a chase: police leave and roadblocks come down.
+func clearPursuit() {
+	for _, rb := range roadblocks {
+		if chunk := chunks[rb.Chunk]; chunk != nil {
+			removeCollider(chunk, rb.Box)
+		}
+	}
+	wanted, policeCars, roadblocks = 0, nil, nil
+	hideTimer, arrestTimer = 0, 0
+}
+
+// updatePolice checks speed cameras, brings in and drives police cars, and
+// decides whether the car has escaped or been arrested.
+func updatePolice() {
+	dt := rl.GetFrameTime()
+	speed := float32(math.Abs(float64(car.speed)))
+	trapFlashTime -= dt
+
+	coord := getChunkCoord(car.position)
+	if trapFlashed && coord != flashedTrap {
+		trapFlashed = false
+	}
+	if chunk := chunks[coord]; chunk != nil && !trapFlashed {
+		if trap := chunk.SpeedTrap; trap != nil && rl.Vector3Distance(*trap, car.position) < ROAD_WIDTH+2 && speed > speedLimits[chunk.Type] {
+			flashedTrap, trapFlashed, trapFlashTime = coord, true, 0.2
+			raiseWanted(fmt.Sprintf("Speed camera: %s", formatSpeed(speed)))
+		}
+	}
+
+	if wanted == 0 {
+		return
+	}
+
+	// Keep one car per wanted level on the way.
+	kept := policeCars[:0]
+	for _, p := range policeCars {
+		if rl.Vector3Distance(p.Position, car.position) < POLICE_DESPAWN_RANGE {
+			kept = append(kept, p)
+		}
+	}
+	policeCars = kept
+	policeSpawnTimer -= dt
+	if len(policeCars) < wanted && policeSpawnTimer <= 0 {
+		spawnPolice()
+		policeSpawnTimer = 3
+	}
+
+	seen := false
+	near := 0
+	for i := range policeCars {
+		p := &policeCars[i]
+		drivePolice(p, dt)
+		distance := rl.Vector3Distance(p.Position, car.position)
+		if distance < POLICE_SIGHT_RANGE && !sightBlocked(p.Position, car.position) {
+			seen = true
+		}
+		if distance < ARREST_RANGE {
+			near++
+		}
+	}
+
+	if seen {
+		hideTimer = 0
+	} else if hideTimer += dt; hideTimer >= ESCAPE_TIME {
+		clearPursuit()
+		notify("You lost the police")
+		return
+	}
+
+	// Boxed in: two cars alongside, or one while pinned against something.
+	if speed < 1 && (near >= 2 || (near == 1 && car.colliding)) {
+		arrestTimer += dt
+	} else {
+		arrestTimer = 0
+	}
+	if arrestTimer >= ARREST_TIME {
+		fine := float32(100 * wanted)
+		cash = float32(math.Max(0, float64(cash-fine)))
+		activeMission = nil
+		clearPursuit()
+		notify(fmt.Sprintf("Busted! Fined $%.0f", fine))
+	}
+}
+
+// spawnPolice brings a police car in at the center of a loaded chunk at a distance.
+func spawnPolice() {
+	var options []rl.Vector3
+	for coord := range chunks {
+		center := chunkCenter(coord)
+		distance := rl.Vector3Distance(center, car.position)
+		if distance > POLICE_SPAWN_MIN && distance < POLICE_SPAWN_MAX && !policeNear(center, 5) {
+			options = append(options, center)
+		}
+	}
+	if len(options) == 0 {
+		return
+	}
+	position := options[rand.Intn(len(options))]
+	to := rl.Vector3Subtract(car.position, position)
+	policeCars = append(policeCars, policeCar{Position: position, Yaw: float32(math.Atan2(float64(to.Z), float64(to.X)))})
+}
+
+// drivePolice heads for the car at a speed that rises with the wanted level,
+// slowing to pull up alongside and swerving around props.
+func drivePolice(p *policeCar, dt float32) {
+	to := rl.Vector3Subtract(car.position, p.Position)
+	to.Y = 0
+	distance := rl.Vector3Length(to)
+	target := 18 + 3*float32(wanted)
+	target = float32(math.Min(float64(target), math.Max(0, float64((distance-3)*2))))
+	if p.Speed < target {
+		p.Speed = float32(math.Min(float64(target), float64(p.Speed+8*dt)))
+	} else {
+		p.Speed = float32(math.Max(float64(target), float64(p.Speed-15*dt)))
+	}
+
+	desired := math.Atan2(float64(to.Z), float64(to.X))
+	for _, swerve := range []float64{0, 0.6, -0.6, 1.2, -1.2} {
+		yaw := desired + swerve
+		probe := rl.Vector3{X: p.Position.X + float32(math.Cos(yaw))*4, Z: p.Position.Z + float32(math.Sin(yaw))*4}
+		if !insideCollider(probe) {
+			desired = yaw
+			break
+		}
+	}
+	diff := math.Remainder(desired-float64(p.Yaw), 2*math.Pi)
+	p.Yaw += float32(rl.Clamp(float32(diff), -2.5*dt, 2.5*dt))
+
+	heading := rl.Vector3{X: float32(math.Cos(float64(p.Yaw))), Z: float32(math.Sin(float64(p.Yaw)))}
+	next := rl.Vector3Add(p.Position, rl.Vector3Scale(heading, p.Speed*dt))
+	if insideCollider(next) || rl.Vector3Distance(next, car.position) < 2.5 {
+		p.Speed = 0
+		return
+	}
+	p.Position = next
+}
+
+// sightBlocked reports whether a prop stands between two points.
+func sightBlocked(from, to rl.Vector3) bool {
+	from.Y, to.Y = 1, 1
+	direction := rl.Vector3Subtract(to, from)
+	distance := rl.Vector3Length(direction)
+	ray := rl.Ray{Position: from, Direction: rl.Vector3Scale(direction, 1/distance)}
+	middle := rl.Vector3Lerp(from, to, 0.5)
+	for _, box := range nearbyColliders(middle, 2) {
+		if hit := rl.GetRayCollisionBox(ray, box); hit.Hit && hit.Distance < distance {
+			return true
+		}
+	}
+	return false
+}
+
+// wantedLabel is the HUD line for the wanted level, which says when no police car can see the car.
+func wantedLabel() string {
+	if hideTimer > 0 {
+		return "Hiding: " + strings.Repeat("*", wanted)
+	}
+	return "Wanted: " + strings.Repeat("*", wanted)
+}
+
+// policeMarkers returns police cars and roadblocks for the minimap.
+func policeMarkers() []mapMarker {
+	var markers []mapMarker
+	for _, p := range policeCars {
+		markers = append(markers, mapMarker{Position: p.Position, Color: rl.Blue})
+	}
+	for _, rb := range roadblocks {
+		markers = append(markers, mapMarker{Position: rl.Vector3Lerp(rb.Box.Min, rb.Box.Max, 0.5), Color: rl.Red})
+	}
+	return markers
+}
+
+// drawPolice draws speed cameras, roadblocks and police cars with flashing light bars.
+func drawPolice() {
+	center := getChunkCoord(car.position)
+	for i := center.X - 1; i <= center.X+1; i++ {
+		for j := center.Y - 1; j <= center.Y+1; j++ {
+			chunk := chunks[Coord{i, j}]
+			if chunk == nil {
+				continue
+			}
+			if trap := chunk.SpeedTrap; trap != nil {
+				renderer.DrawCube(rl.Vector3{X: trap.X, Y: 1.5, Z: trap.Z}, rl.Vector3{X: 0.2, Y: 3, Z: 0.2}, rl.DarkGray)
+				renderer.DrawCube(rl.Vector3{X: trap.X, Y: 3.2, Z: trap.Z}, rl.Vector3{X: 0.6, Y: 0.5, Z: 0.6}, rl.Gray)
+				if trapFlashTime > 0 && chunk.Coord == flashedTrap {
+					renderer.DrawSphere(rl.Vector3{X: trap.X, Y: 3.2, Z: trap.Z}, 0.8, rl.White)
+				}
+			}
+		}
+	}
+
+	for i, rb := range roadblocks {
+		color := rl.Red
+		if i%2 == 1 {
+			color = rl.White
+		}
+		renderer.DrawCube(rl.Vector3Lerp(rb.Box.Min, rb.Box.Max, 0.5), rl.Vector3Subtract(rb.Box.Max, rb.Box.Min), color)
+	}
+
+	transforms := make([]rl.Matrix, len(policeCars))
+	flash := int(rl.GetTime()*4)%2 == 0
+	for i, p := range policeCars {
+		transforms[i] = rl.MatrixMultiply(rl.MatrixRotateY(math.Pi/2-p.Yaw), rl.MatrixTranslate(p.Position.X, p.Position.Y, p.Position.Z))
+		left, right := rl.Red, rl.Blue
+		if flash {
+			left, right = right, left
+		}
+		side := rl.Vector3{X: -float32(math.Sin(float64(p.Yaw))) * 0.25, Z: float32(math.Cos(float64(p.Yaw))) * 0.25}
+		top := rl.Vector3Add(p.Position, rl.Vector3{Y: 0.35})
+		renderer.DrawCube(rl.Vector3Add(top, side), rl.Vector3{X: 0.3, Y: 0.15, Z: 0.3}, left)
+		renderer.DrawCube(rl.Vector3Subtract(top, side), rl.Vector3{X: 0.3, Y: 0.15, Z: 0.3}, right)
+	}
+	renderer.DrawModel(trafficModel, transforms, rl.White)
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// PropType identifies a kind of object placed by the chunk generator.
+type PropType int
+
+const (
+	PropBuilding PropType = iota
+	PropStore
+	PropCactus
+	PropTree
+	PropIgloo
+	PropStreetlight
+	PropSign
+	PropFence
+)
+
+// propDef describes a prop type. Every instance of a type shares one model,
+// created once by initProps from the asset manifest or, failing that, genMesh.
+type propDef struct {
+	Name    string     // key in the asset manifest
+	Size    rl.Vector3 // bounding box, base on the ground
+	Color   rl.Color   // primitive color
+	Marker  bool       // shown as a point of interest on the maps
+	Mass    PropMass
+	Weight  float32 // kg, for light props
+	genMesh func() rl.Mesh
+
+	model rl.Model
+	local rl.Matrix // places the model with its base at the instance position
+}
+
+// propDefs is the prop registry, indexed by PropType.
+var propDefs = []propDef{
+	PropBuilding: {Name: "building", Size: rl.Vector3{X: 10, Y: 50, Z: 10}, Color: rl.Blue,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(10, 50, 10) }},
+	PropStore: {Name: "store", Size: rl.Vector3{X: 15, Y: 10, Z: 15}, Color: rl.Purple, Marker: true,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(15, 10, 15) }},
+	PropCactus: {Name: "cactus", Size: rl.Vector3{X: 1, Y: 5, Z: 1}, Color: rl.Green, Mass: MassLight, Weight: 60,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(1, 5, 1) }},
+	PropTree: {Name: "tree", Size: rl.Vector3{X: 2, Y: 10, Z: 2}, Color: rl.DarkGreen,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(2, 10, 2) }},
+	PropIgloo: {Name: "igloo", Size: rl.Vector3{X: 10, Y: 10, Z: 10}, Color: rl.White,
+		genMesh: func() rl.Mesh { return rl.GenMeshSphere(5, 16, 16) }},
+	PropStreetlight: {Name: "streetlight", Size: rl.Vector3{X: 0.3, Y: 6, Z: 0.3}, Color: rl.DarkGray,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(0.3, 6, 0.3) }},
+	PropSign: {Name: "sign", Size: rl.Vector3{X: 0.8, Y: 2.5, Z: 0.2}, Color: rl.Yellow, Mass: MassLight, Weight: 25,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(0.8, 2.5, 0.2) }},
+	PropFence: {Name: "fence", Size: rl.Vector3{X: 3, Y: 1, Z: 0.2}, Color: rl.Brown, Mass: MassLight, Weight: 40,
+		genMesh: func() rl.Mesh { return rl.GenMeshCube(3, 1, 0.2) }},
+}
+
+// PropMass is how a prop reacts when the car runs into it.
+type PropMass int
+
+const (
+	MassHeavy PropMass = iota // solid: stops the car
+	MassLight                 // knocked over, slowing the car a little
+)
+
+// chunkPropSpawn says which prop a chunk type spawns and how many placement attempts it makes.
+type chunkPropSpawn struct {
+	Type  PropType
+	Count int
+}
+
+var chunkProps = map[int]chunkPropSpawn{
+	City:       {PropBuilding, 5},
+	Commercial: {PropStore, 3},
+	Desert:     {PropCactus, 10},
+	Forest:     {PropTree, 20},
+	Snow:       {PropIgloo, 2},
+}
+
+// roadsideProps says which prop lines the main roads of a chunk type, and how
+// many. Fence panels run east-west, so they only line the east-west road.
+var roadsideProps = map[int]chunkPropSpawn{
+	Highway: {PropSign, 2},
+	Forest:  {PropFence, 3},
+}
+
+// propInstance is one placed prop.
+type propInstance struct {
+	Type      PropType
+	Position  rl.Vector3 // center of the base
+	Transform rl.Matrix
+	// A knocked-over light prop slides and tips over about TiltAxis.
+	Knocked   bool
+	Velocity  rl.Vector3
+	TiltAxis  rl.Vector3
+	Tilt      float32
+	TiltSpeed float32
+}
+
+// newPropInstance places a prop of the given type with its base at (x, z).
+func newPropInstance(t PropType, x, z float32) propInstance {
+	return propInstance{
+		Type:      t,
+		Position:  rl.Vector3{X: x, Z: z},
+		Transform: rl.MatrixMultiply(propDefs[t].local, rl.MatrixTranslate(x, 0, z)),
+	}
+}
+
+// bounds returns the prop's collision box.
+func (p propInstance) bounds() rl.BoundingBox {
+	size := propDefs[p.Type].Size
+	return rl.BoundingBox{
+		Min: rl.Vector3{X: p.Position.X - size.X/2, Y: p.Position.Y, Z: p.Position.Z - size.Z/2},
+		Max: rl.Vector3{X: p.Position.X + size.X/2, Y: p.Position.Y + size.Y, Z: p.Position.Z + size.Z/2},
+	}
+}
+
+// Instancing shader: the per-instance model matrix arrives as a vertex attribute.
+// Fragments are lit by the sun, nearby streetlights and the car's headlights
+// (see applyLighting) and fade into weather and distance fog (see applyFog).
+const instancingVS = `#version 330
+in vec3 vertexPosition;
+in vec2 vertexTexCoord;
+in vec3 vertexNormal;
+in mat4 instanceTransform;
+uniform mat4 mvp;
+out vec3 fragPosition;
+out vec2 fragTexCoord;
+out vec3 fragNormal;
+void main() {
+    vec4 world = instanceTransform*vec4(vertexPosition, 1.0);
+    fragPosition = world.xyz;
+    fragTexCoord = vertexTexCoord;
+    fragNormal = normalize(mat3(instanceTransform)*vertexNormal);
+    gl_Position = mvp*world;
+}
+`
+
+const instancingFS = `#version 330
+#define MAX_LIGHTS 16
+in vec3 fragPosition;
+in vec2 fragTexCoord;
+in vec3 fragNormal;
+uniform sampler2D texture0;
+uniform vec4 colDiffuse;
+uniform vec3 sunDir;
+uniform vec3 sunColor;
+uniform vec3 ambient;
+uniform vec3 lightPos[MAX_LIGHTS];
+uniform vec3 lightColor[MAX_LIGHTS];
+uniform float lightCount;
+uniform vec3 headPos;
+uniform vec3 headDir;
+uniform float headOn;
+uniform vec3 viewPos;
+uniform vec3 fogColor;
+uniform float fogDensity;
+uniform float fogStart;
+uniform float fogEnd;
+out vec4 finalColor;
+void main() {
+    vec4 base = texture(texture0, fragTexCoord)*colDiffuse;
+    vec3 n = normalize(fragNormal);
+    vec3 light = ambient + sunColor*max(dot(n, -sunDir), 0.0);
+    for (int i = 0; i < MAX_LIGHTS; i++) {
+        if (float(i) >= lightCount) break;
+        vec3 d = lightPos[i] - fragPosition;
+        float dist = length(d);
+        float att = clamp(1.0 - dist/15.0, 0.0, 1.0);
+        light += lightColor[i]*att*att*max(dot(n, d/dist), 0.2);
+    }
+    if (headOn > 0.5) {
+        vec3 d = fragPosition - headPos;
+        float dist = length(d);
+        vec3 dir = d/dist;
+        float cone = smoothstep(0.85, 0.95, dot(dir, normalize(headDir)));
+        float att = clamp(1.0 - dist/40.0, 0.0, 1.0);
+        light += vec3(1.0, 0.95, 0.8)*1.5*cone*att*max(dot(n, -dir), 0.0);
+    }
+    float dist = length(viewPos - fragPosition);
+    float fog = min(exp(-pow(fogDensity*dist, 2.0)), clamp((fogEnd - dist)/(fogEnd - fogStart), 0.0, 1.0));
+    finalColor = vec4(mix(fogColor, base.rgb*light, clamp(fog, 0.0, 1.0)), base.a);
+}
+`
+
+var (
+	instancingShader rl.Shader
+	// False when the instancing shader failed to compile; meshes are then drawn one by one.
+	instancingSupported bool
+)
+
+var (
+	groundMesh      rl.Mesh
+	groundMaterials []rl.Material // by chunk type
+	roadMeshH       rl.Mesh       // CHUNK_SIZE along X
+	roadMeshV       rl.Mesh       // CHUNK_SIZE along Z
+	roadMaterials   []rl.Material // by road type
+	// Sidewalks beside City and Commercial roads, doubling as crosswalks where they cross a road.
+	sidewalkMeshH    rl.Mesh
+	sidewalkMeshV    rl.Mesh
+	sidewalkMaterial rl.Material
+	// Unit cube scaled into each landmark piece; recolored for each batch.
+	pieceMesh     rl.Mesh
+	pieceMaterial rl.Material
+)
+
+// newInstancedMaterial creates a flat-colored material that uses the instancing shader when available.
+func newInstancedMaterial(color rl.Color) rl.Material {
+	material := rl.LoadMaterialDefault()
+	if instancingSupported {
+		material.Shader = instancingShader
+	}
+	material.Maps.Color = color
+	return material
+}
+
+// useInstancingShader switches every material of a model to the instancing shader.
+func useInstancingShader(model rl.Model) {
+	if !instancingSupported {
+		return
+	}
+	materials := model.GetMaterials()
+	for i := range materials {
+		materials[i].Shader = instancingShader
+	}
+}
+
+// initProps creates the shared models and materials for props, ground and roads.
+func initProps() {
+	instancingShader = rl.LoadShaderFromMemory(instancingVS, instancingFS)
+	// raylib falls back to its default shader when compilation fails.
+	instancingSupported = instancingShader.ID != rl.GetShaderIdDefault()
+	if instancingSupported {
+		instancingShader.UpdateLocation(rl.ShaderLocMatrixModel, rl.GetShaderLocationAttrib(instancingShader, "instanceTransform"))
+	}
+
+	loadAssetManifest()
+	for i := range propDefs {
+		def := &propDefs[i]
+		if model, local, ok := loadAsset(manifest.Props[def.Name]); ok {
+			def.model, def.local = model, local
+		} else {
+			// Primitive meshes are centered, so lift them by half their height.
+			def.model = rl.LoadModelFromMesh(def.genMesh())
+			def.model.GetMaterials()[0].Maps.Color = def.Color
+			def.local = rl.MatrixTranslate(0, def.Size.Y/2, 0)
+		}
+		useInstancingShader(def.model)
+	}
+
+	groundMesh = rl.GenMeshPlane(CHUNK_SIZE, CHUNK_SIZE, 1, 1)
+	for _, color := range typeColors {
+		groundMaterials = append(groundMaterials, newInstancedMaterial(color))
+	}
+	roadMeshH = rl.GenMeshPlane(CHUNK_SIZE, ROAD_WIDTH, 1, 1)
+	roadMeshV = rl.GenMeshPlane(ROAD_WIDTH, CHUNK_SIZE, 1, 1)
+	for _, color := range roadColors {
+		roadMaterials = append(roadMaterials, newInstancedMaterial(color))
+	}
+	sidewalkMeshH = rl.GenMeshPlane(CHUNK_SIZE, SIDEWALK_WIDTH, 1, 1)
+	sidewalkMeshV = rl.GenMeshPlane(SIDEWALK_WIDTH, CHUNK_SIZE, 1, 1)
+	sidewalkMaterial = newInstancedMaterial(rl.LightGray)
+	pieceMesh = rl.GenMeshCube(1, 1, 1)
+	pieceMaterial = newInstancedMaterial(rl.White)
+}
+
+// initPropsHeadless fills the prop, ground and road tables with CPU-only
+// materials so the world can be drawn to a recordingRenderer without a GPU.
+func initPropsHeadless() {
+	instancingSupported = false
+	for i := range propDefs {
+		def := &propDefs[i]
+		def.model = headlessModel(def.Color)
+		def.local = rl.MatrixTranslate(0, def.Size.Y/2, 0)
+	}
+	groundMaterials, roadMaterials = nil, nil
+	for _, color := range typeColors {
+		groundMaterials = append(groundMaterials, rl.Material{Maps: &rl.MaterialMap{Color: color}})
+	}
+	for _, color := range roadColors {
+		roadMaterials = append(roadMaterials, rl.Material{Maps: &rl.MaterialMap{Color: color}})
+	}
+	sidewalkMaterial = rl.Material{Maps: &rl.MaterialMap{Color: rl.LightGray}}
+	pieceMaterial = rl.Material{Maps: &rl.MaterialMap{Color: rl.White}}
+}
+
+// drawMeshInstanced adapts to rl.DrawMeshInstanced taking the instance count as
+// int (cgo builds) or int32 (purego builds).
+func drawMeshInstanced[N int | int32](draw func(rl.Mesh, rl.Material, []rl.Matrix, N), mesh rl.Mesh, material rl.Material, transforms []rl.Matrix) {
+	draw(mesh, material, transforms, N(len(transforms)))
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Checkpoints per route; each one is a main-road intersection one to three chunks on from the last.
+const RACE_CHECKPOINTS = 6
+
+// A checkpoint is passed within this distance of its center, in meters.
+const CHECKPOINT_RADIUS float32 = 8
+
+// Seconds of countdown before the clock starts.
+const RACE_COUNTDOWN float32 = 3
+
+// Best race times for every seed and route, shared by all save slots.
+const RECORDS_FILE = "records.json"
+
+// raceStatus is the phase of the current race.
+type raceStatus int
+
+const (
+	RaceOff raceStatus = iota
+	RaceCountdown
+	RaceRunning
+	RaceFinished
+)
+
+// raceRoute is a sequence of checkpoints starting at the center of a chunk.
+type raceRoute struct {
+	Start       Coord
+	Checkpoints []rl.Vector3
+}
+
+// raceRecord is the best run of a route.
+type raceRecord struct {
+	Time   float32   `json:"time"`
+	Splits []float32 `json:"splits"`
+}
+
+var (
+	raceState raceStatus
+	route     raceRoute
+	// Countdown left, then elapsed race time.
+	raceTimer float32
+	// Index of the next checkpoint to pass.
+	raceNext   int
+	raceSplits []float32
+	// Seconds spent heading away from the next checkpoint.
+	wrongWayTimer float32
+	// Best run of the current route before this race, if any.
+	raceBest      *raceRecord
+	raceNewRecord bool
+	// Best times keyed by raceKey; loaded on first use.
+	raceRecords map[string]raceRecord
+)
+
+// chunkCenter returns the intersection of the main roads of a chunk.
+func chunkCenter(coord Coord) rl.Vector3 {
+	return rl.Vector3{X: float32(coord.X)*CHUNK_SIZE + CHUNK_SIZE/2, Z: float32(coord.Y)*CHUNK_SIZE + CHUNK_SIZE/2}
+}
+
+// raceKey identifies a route: the world seed and the chunk it starts in.
+func raceKey(start Coord) string {
+	return fmt.Sprintf("%d:%d,%d", worldSeed, start.X, start.Y)
+}
+
+// generateRoute lays out checkpoints along the main roads from start. Every
+// chunk has a "+" road through its center, so going straight or turning at
+// a center always stays on the road. Routes never cross themselves.
+func generateRoute(start Coord) raceRoute {
+	r := seededRandom("race", start.X, start.Y)
+
+	route := raceRoute{Start: start}
+	visited := map[Coord]bool{start: true}
+	at, heading := start, r.Intn(4)
+	for len(route.Checkpoints) < RACE_CHECKPOINTS {
+		placed := false
+		for try := 0; try < 8 && !placed; try++ {
+			dir := heading
+			if len(route.Checkpoints) > 0 {
+				dir = (heading + []int{0, 1, 3}[r.Intn(3)]) % 4
+			}
+			length := 1 + r.Intn(3)
+			d := trafficDirs[dir]
+			var leg []Coord
+			for k := 1; k <= length; k++ {
+				next := Coord{at.X + k*int(d.X), at.Y + k*int(d.Z)}
+				if visited[next] {
+					break
+				}
+				leg = append(leg, next)
+			}
+			if len(leg) < length {
+				continue
+			}
+			for _, c := range leg {
+				visited[c] = true
+			}
+			at, heading, placed = leg[len(leg)-1], dir, true
+			route.Checkpoints = append(route.Checkpoints, chunkCenter(at))
+		}
+		if !placed {
+			break
+		}
+	}
+	return route
+}
+
+// startRace lines the car up at the center of its chunk facing the first
+// checkpoint and starts the countdown.
+func startRace() {
+	route = generateRoute(getChunkCoord(car.position))
+	if len(route.Checkpoints) == 0 {
+		return
+	}
+	start := chunkCenter(route.Start)
+	first := rl.Vector3Subtract(route.Checkpoints[0], start)
+	car.position = start
+	car.yaw = float32(math.Atan2(float64(first.Z), float64(first.X)))
+	car.pitch, car.speed, car.steering, car.velocity = 0, 0, 0, rl.Vector3{}
+
+	// Clear the grid of traffic.
+	kept := trafficCars[:0]
+	for _, t := range trafficCars {
+		if rl.Vector3Distance(t.Position, start) > 15 {
+			kept = append(kept, t)
+		}
+	}
+	trafficCars = kept
+
+	loadRaceRecords()
+	raceBest = nil
+	if best, ok := raceRecords[raceKey(route.Start)]; ok {
+		raceBest = &best
+	}
+	raceGhost, ghostRecording = loadGhost(route.Start), nil
+	raceState, raceTimer, raceNext, raceSplits = RaceCountdown, RACE_COUNTDOWN, 0, nil
+	wrongWayTimer, raceNewRecord = 0, false
+}
+
+// raceHoldingCar reports whether the car must stay on the grid.
+func raceHoldingCar() bool {
+	return raceState == RaceCountdown
+}
+
+// updateRace handles the race keys, the countdown, checkpoints and the wrong-way warning.
+func updateRace() {
+	dt := rl.GetFrameTime()
+	if rl.IsKeyPressed(rl.KeyR) {
+		switch raceState {
+		case RaceOff, RaceFinished:
+			if activeMission != nil {
+				notify("Finish the job first")
+				return
+			}
+			startRace()
+		default:
+			raceState = RaceOff
+			notify("Race abandoned")
+		}
+		return
+	}
+
+	switch raceState {
+	case RaceCountdown:
+		raceTimer -= dt
+		if raceTimer <= 0 {
+			raceState, raceTimer = RaceRunning, 0
+			appendGhostSample()
+		}
+	case RaceRunning:
+		raceTimer += dt
+		recordGhost()
+		next := route.Checkpoints[raceNext]
+		to := rl.Vector3Subtract(next, car.position)
+		to.Y = 0
+		if rl.Vector3Length(to) < CHECKPOINT_RADIUS {
+			raceSplits = append(raceSplits, raceTimer)
+			raceNext++
+			if raceNext == len(route.Checkpoints) {
+				finishRace()
+				return
+			}
+			wrongWayTimer = 0
+			return
+		}
+		// Compare the direction of travel, not the nose, so reversing toward the checkpoint is fine.
+		travel := rl.Vector3Scale(carForward(), float32(math.Copysign(1, float64(car.speed))))
+		travel.Y = 0
+		if math.Abs(float64(car.speed)) > 2 && rl.Vector3DotProduct(rl.Vector3Normalize(travel), rl.Vector3Normalize(to)) < -0.5 {
+			wrongWayTimer += dt
+		} else {
+			wrongWayTimer = 0
+		}
+	case RaceFinished:
+		if rl.IsKeyPressed(rl.KeyEnter) {
+			raceState = RaceOff
+		}
+	}
+}
+
+// finishRace stops the clock and stores the run if it beats the record.
+func finishRace() {
+	raceState = RaceFinished
+	saveGhost()
+	emitEvent(EventRaceFinished, raceTimer)
+	if raceBest != nil && raceBest.Time <= raceTimer {
+		return
+	}
+	raceNewRecord = true
+	raceRecords[raceKey(route.Start)] = raceRecord{Time: raceTimer, Splits: raceSplits}
+	if err := writeRaceRecords(); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not save race records: %v", err)
+	}
+}
+
+// loadRaceRecords reads the best times once; a missing file means no records yet.
+func loadRaceRecords() {
+	if raceRecords != nil {
+		return
+	}
+	raceRecords = map[string]raceRecord{}
+	bytes, err := os.ReadFile(filepath.Join(SAVE_DIR, RECORDS_FILE))
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(bytes, &raceRecords); err != nil {
+		rl.TraceLog(rl.LogWarning, "could not read race records: %v", err)
+	}
+}
+
+// writeRaceRecords stores the best times of every route.
+func writeRaceRecords() error {
+	bytes, err := json.MarshalIndent(raceRecords, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(SAVE_DIR, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(SAVE_DIR, RECORDS_FILE), bytes, 0o644)
+}
+
+// bestSplit returns the record's time at checkpoint i, if there is a record.
+func bestSplit(i int) (float32, bool) {
+	if raceBest == nil || i >= len(raceBest.Splits) {
+		return 0, false
+	}
+	return raceBest.Splits[i], true
+}
+
+// formatRaceTime formats seconds as m:ss.cc.
+func formatRaceTime(s float32) string {
+	hundredths := int(s*100 + 0.5)
+	return fmt.Sprintf("%d:%02d.%02d", hundredths/6000, hundredths/100%60, hundredths%100)
+}
+
+// formatDelta formats a time difference with its sign; negative is ahead of the record.
+func formatDelta(d float32) string {
+	return fmt.Sprintf("%+.2f", d)
+}
+
+// raceMarkers returns the remaining checkpoints for the minimap.
+func raceMarkers() []mapMarker {
+	if raceState != RaceCountdown && raceState != RaceRunning {
+		return nil
+	}
+	var markers []mapMarker
+	for i := raceNext; i < len(route.Checkpoints); i++ {
+		color := rl.Orange
+		if i == raceNext {
+			color = rl.Yellow
+		}
+		markers = append(markers, mapMarker{Position: route.Checkpoints[i], Color: color})
+	}
+	return markers
+}
+
+// drawRaceCheckpoints draws gates over the road at the next two checkpoints,
+// with a beacon above the next one so it can be seen over buildings.
+func drawRaceCheckpoints() {
+	if raceState != RaceCountdown && raceState != RaceRunning {
+		return
+	}
+	for i := raceNext; i < len(route.Checkpoints) && i <= raceNext+1; i++ {
+		from := chunkCenter(route.Start)
+		if i > 0 {
+			from = route.Checkpoints[i-1]
+		}
+		at := route.Checkpoints[i]
+		d := rl.Vector3Normalize(rl.Vector3Subtract(at, from))
+		across := rl.Vector3{X: -d.Z, Z: d.X}
+		color := rl.Yellow
+		if i == len(route.Checkpoints)-1 {
+			color = rl.Green
+		}
+		if i > raceNext {
+			color = rl.Fade(color, 0.4)
+		}
+		span := ROAD_WIDTH + 1
+		for _, side := range []float32{-span / 2, span / 2} {
+			post := rl.Vector3Add(at, rl.Vector3Scale(across, side))
+			post.Y = 2
+			renderer.DrawCube(post, rl.Vector3{X: 0.4, Y: 4, Z: 0.4}, color)
+		}
+		banner := rl.Vector3{X: 0.3, Y: 0.6, Z: 0.3}
+		if across.X != 0 {
+			banner.X = span
+		} else {
+			banner.Z = span
+		}
+		renderer.DrawCube(rl.Vector3{X: at.X, Y: 4, Z: at.Z}, banner, color)
+		if i == raceNext {
+			renderer.DrawCube(rl.Vector3{X: at.X, Y: 25, Z: at.Z}, rl.Vector3{X: 0.5, Y: 42, Z: 0.5}, rl.Fade(color, 0.35))
+		}
+	}
+}
+
+// drawRaceHUD draws the countdown, the clock and splits, the wrong-way
+// warning and the results screen.
+func drawRaceHUD() {
+	screenW, _ := renderer.ScreenSize()
+	top := rl.Rectangle{Width: screenW, Y: ui(45)}
+	switch raceState {
+	case RaceCountdown:
+		count := fmt.Sprintf("%d", int(math.Ceil(float64(raceTimer))))
+		drawUITextCentered(count, uiRect(AnchorCenter, 0, -80, 200, 80), 0, 80, rl.Yellow)
+	case RaceRunning:
+		if raceTimer < 1 {
+			drawUITextCentered("GO!", uiRect(AnchorCenter, 0, -80, 200, 80), 0, 80, rl.Green)
+		}
+		drawUITextCentered(fmt.Sprintf("Checkpoint %d/%d   %s", raceNext+1, len(route.Checkpoints), formatRaceTime(raceTimer)), top, 0, 24, rl.Black)
+		if delta, ok := ghostDelta(); ok {
+			color := rl.DarkGreen
+			if delta > 0 {
+				color = rl.Maroon
+			}
+			drawUITextCentered("Ghost "+formatDelta(delta), top, 30, 20, color)
+		}
+		if n := len(raceSplits); n > 0 && raceTimer-raceSplits[n-1] < 3 {
+			split := "Split " + formatRaceTime(raceSplits[n-1])
+			color := rl.Black
+			if best, ok := bestSplit(n - 1); ok {
+				delta := raceSplits[n-1] - best
+				split += "  " + formatDelta(delta)
+				color = rl.DarkGreen
+				if delta > 0 {
+					color = rl.Maroon
+				}
+			}
+			drawUITextCentered(split, top, 55, 20, color)
+		}
+		if wrongWayTimer > 1 {
+			drawUITextCentered("WRONG WAY", uiRect(AnchorCenter, 0, -40, 300, 50), 0, 50, rl.Red)
+		}
+	case RaceFinished:
+		drawRaceResults()
+	}
+}
+
+// drawRaceResults draws the final time, the record and each split.
+func drawRaceResults() {
+	panel := uiRect(AnchorCenter, 0, 0, 320, float32(170+25*len(raceSplits)))
+	renderer.DrawRectangle(panel, rl.Fade(rl.LightGray, 0.9))
+	drawUITextCentered("Race complete", panel, 15, 30, rl.Black)
+	drawUITextCentered("Time "+formatRaceTime(raceTimer), panel, 55, 24, rl.Black)
+	switch {
+	case raceNewRecord && raceBest != nil:
+		drawUITextCentered("New record! "+formatDelta(raceTimer-raceBest.Time), panel, 85, 20, rl.DarkGreen)
+	case raceNewRecord:
+		drawUITextCentered("New record!", panel, 85, 20, rl.DarkGreen)
+	default:
+		drawUITextCentered("Best "+formatRaceTime(raceBest.Time), panel, 85, 20, rl.Black)
+	}
+	for i, split := range raceSplits {
+		line := fmt.Sprintf("%d.  %s", i+1, formatRaceTime(split))
+		if best, ok := bestSplit(i); ok {
+			line += "  " + formatDelta(split-best)
+		}
+		drawUITextCentered(line, panel, float32(120+25*i), 20, rl.Black)
+	}
+	drawUITextCentered("Enter: close  R: race again", panel, float32(130+25*len(raceSplits)), 20, rl.DarkGray)
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Renderer is the set of draw primitives the game uses. Drawing code goes
+// through the global renderer instead of calling raylib, so the same frame can
+// be drawn to the window (rlRenderer) or recorded without a GPU
+// (recordingRenderer). Photo mode's post-processing still talks to raylib.
+type Renderer interface {
+	ScreenSize() (width, height float32)
+	MeasureText(text string, size int32) int32
+
+	ClearBackground(color rl.Color)
+	BeginMode3D(camera rl.Camera3D)
+	EndMode3D()
+	BeginScissorMode(rect rl.Rectangle)
+	EndScissorMode()
+
+	DrawRectangle(rect rl.Rectangle, color rl.Color)
+	DrawRectangleLines(rect rl.Rectangle, thick float32, color rl.Color)
+	// DrawRectanglePro rotates rect by rotation degrees around origin (relative to rect).
+	DrawRectanglePro(rect rl.Rectangle, origin rl.Vector2, rotation float32, color rl.Color)
+	DrawCircle(center rl.Vector2, radius float32, color rl.Color)
+	DrawTriangle(a, b, c rl.Vector2, color rl.Color)
+	DrawText(text string, x, y, size int32, color rl.Color)
+	DrawTexture(texture rl.Texture2D, source, dest rl.Rectangle, tint rl.Color)
+
+	// DrawMeshes draws a mesh at every transform.
+	DrawMeshes(mesh rl.Mesh, material rl.Material, transforms []rl.Matrix)
+	// DrawModel draws every mesh of a model at every transform through the lit
+	// shader, with its material colors multiplied by tint.
+	DrawModel(model rl.Model, transforms []rl.Matrix, tint rl.Color)
+	DrawSphere(center rl.Vector3, radius float32, color rl.Color)
+	DrawCube(center, size rl.Vector3, color rl.Color)
+	DrawLine3D(start, end rl.Vector3, color rl.Color)
+	// DrawSky draws the sky dome centered on center, behind everything drawn after it.
+	DrawSky(center rl.Vector3, horizon, zenith rl.Color)
+}
+
+var renderer Renderer = rlRenderer{}
+
+// rlRenderer draws to the raylib window.
+type rlRenderer struct{}
+
+func (rlRenderer) ScreenSize() (float32, float32) {
+	return float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight())
+}
+
+func (rlRenderer) MeasureText(text string, size int32) int32 { return rl.MeasureText(text, size) }
+
+func (rlRenderer) ClearBackground(color rl.Color) { rl.ClearBackground(color) }
+func (rlRenderer) BeginMode3D(camera rl.Camera3D) { rl.BeginMode3D(camera) }
+func (rlRenderer) EndMode3D()                     { rl.EndMode3D() }
+
+func (rlRenderer) BeginScissorMode(rect rl.Rectangle) {
+	rl.BeginScissorMode(int32(rect.X), int32(rect.Y), int32(rect.Width), int32(rect.Height))
+}
+
+func (rlRenderer) EndScissorMode() { rl.EndScissorMode() }
+
+func (rlRenderer) DrawRectangle(rect rl.Rectangle, color rl.Color) { rl.DrawRectangleRec(rect, color) }
+
+func (rlRenderer) DrawRectangleLines(rect rl.Rectangle, thick float32, color rl.Color) {
+	rl.DrawRectangleLinesEx(rect, thick, color)
+}
+
+func (rlRenderer) DrawRectanglePro(rect rl.Rectangle, origin rl.Vector2, rotation float32, color rl.Color) {
+	rl.DrawRectanglePro(rect, origin, rotation, color)
+}
+
+func (rlRenderer) DrawCircle(center rl.Vector2, radius float32, color rl.Color) {
+	rl.DrawCircleV(center, radius, color)
+}
+
+func (rlRenderer) DrawTriangle(a, b, c rl.Vector2, color rl.Color) { rl.DrawTriangle(a, b, c, color) }
+
+func (rlRenderer) DrawText(text string, x, y, size int32, color rl.Color) {
+	rl.DrawText(text, x, y, size, color)
+}
+
+func (rlRenderer) DrawTexture(texture rl.Texture2D, source, dest rl.Rectangle, tint rl.Color) {
+	rl.DrawTexturePro(texture, source, dest, rl.Vector2{}, 0, tint)
+}
+
+// DrawMeshes draws in one call when instancing is supported.
+func (rlRenderer) DrawMeshes(mesh rl.Mesh, material rl.Material, transforms []rl.Matrix) {
+	if len(transforms) == 0 {
+		return
+	}
+	if instancingSupported {
+		drawMeshInstanced(rl.DrawMeshInstanced, mesh, material, transforms)
+		return
+	}
+	for _, transform := range transforms {
+		rl.DrawMesh(mesh, material, transform)
+	}
+}
+
+func (r rlRenderer) DrawModel(model rl.Model, transforms []rl.Matrix, tint rl.Color) {
+	if len(transforms) == 0 {
+		return
+	}
+	placed := make([]rl.Matrix, len(transforms))
+	for i, transform := range transforms {
+		placed[i] = rl.MatrixMultiply(model.Transform, transform)
+	}
+	materials := model.GetMaterials()
+	meshMaterial := unsafe.Slice(model.MeshMaterial, model.MeshCount)
+	for i, mesh := range model.GetMeshes() {
+		material := materials[meshMaterial[i]]
+		if instancingSupported {
+			material.Shader = instancingShader
+		}
+		// Maps is shared with the model, so restore the color after drawing.
+		color := material.Maps.Color
+		material.Maps.Color = rl.ColorTint(color, tint)
+		r.DrawMeshes(mesh, material, placed)
+		material.Maps.Color = color
+	}
+}
+
+func (rlRenderer) DrawSphere(center rl.Vector3, radius float32, color rl.Color) {
+	rl.DrawSphere(center, radius, color)
+}
+
+func (rlRenderer) DrawCube(center, size rl.Vector3, color rl.Color) {
+	rl.DrawCubeV(center, size, color)
+}
+
+func (rlRenderer) DrawLine3D(start, end rl.Vector3, color rl.Color) { rl.DrawLine3D(start, end, color) }
+
+// DrawSky draws the inside of the dome without writing depth.
+func (rlRenderer) DrawSky(center rl.Vector3, horizon, zenith rl.Color) {
+	h, z := rl.ColorNormalize(horizon), rl.ColorNormalize(zenith)
+	rl.SetShaderValue(skyShader, skyHorizonLoc, []float32{h.X, h.Y, h.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(skyShader, skyZenithLoc, []float32{z.X, z.Y, z.Z}, rl.ShaderUniformVec3)
+	rl.DisableBackfaceCulling()
+	rl.DisableDepthMask()
+	rl.DrawMesh(skyMesh, skyMaterial, rl.MatrixTranslate(center.X, center.Y, center.Z))
+	rl.EnableDepthMask()
+	rl.EnableBackfaceCulling()
+}
+
+// DrawCommand is one call captured by recordingRenderer.
+type DrawCommand struct {
+	Op     string
+	Text   string
+	Rect   rl.Rectangle // 2D bounds; DrawCircle keeps its radius in Width
+	Points []rl.Vector3 // vertices, centers or instance positions
+	Color  rl.Color
+}
+
+// String formats the command on one line for golden comparisons.
+func (c DrawCommand) String() string {
+	var b strings.Builder
+	b.WriteString(c.Op)
+	if c.Text != "" {
+		fmt.Fprintf(&b, " %q", c.Text)
+	}
+	if c.Rect != (rl.Rectangle{}) {
+		fmt.Fprintf(&b, " [%.1f %.1f %.1f %.1f]", c.Rect.X, c.Rect.Y, c.Rect.Width, c.Rect.Height)
+	}
+	for _, p := range c.Points {
+		fmt.Fprintf(&b, " (%.2f %.2f %.2f)", p.X, p.Y, p.Z)
+	}
+	fmt.Fprintf(&b, " #%02x%02x%02x%02x", c.Color.R, c.Color.G, c.Color.B, c.Color.A)
+	return b.String()
+}
+
+// recordingRenderer captures draw commands instead of drawing, for checking
+// HUD layout and world drawing on machines without a GPU. Set it up with
+// initHeadless instead of initGame.
+type recordingRenderer struct {
+	Width, Height float32
+	Commands      []DrawCommand
+}
+
+func newRecordingRenderer(width, height float32) *recordingRenderer {
+	return &recordingRenderer{Width: width, Height: height}
+}
+
+func (r *recordingRenderer) record(c DrawCommand) { r.Commands = append(r.Commands, c) }
+
+// Reset clears the recorded commands before the next frame.
+func (r *recordingRenderer) Reset() { r.Commands = r.Commands[:0] }
+
+// String returns the recorded commands, one per line.
+func (r *recordingRenderer) String() string {
+	var b strings.Builder
+	for _, c := range r.Commands {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (r *recordingRenderer) ScreenSize() (float32, float32) { return r.Width, r.Height }
+
+// MeasureText approximates raylib's default font: about 0.6 of the size per character.
+func (r *recordingRenderer) MeasureText(text string, size int32) int32 {
+	return int32(len([]rune(text))) * size * 3 / 5
+}
+
+func (r *recordingRenderer) ClearBackground(color rl.Color) {
+	r.record(DrawCommand{Op: "ClearBackground", Color: color})
+}
+
+func (r *recordingRenderer) BeginMode3D(camera rl.Camera3D) {
+	r.record(DrawCommand{Op: "BeginMode3D", Points: []rl.Vector3{camera.Position, camera.Target}})
+}
+
+func (r *recordingRenderer) EndMode3D() { r.record(DrawCommand{Op: "EndMode3D"}) }
+
+func (r *recordingRenderer) BeginScissorMode(rect rl.Rectangle) {
+	r.record(DrawCommand{Op: "BeginScissorMode", Rect: rect})
+}
+
+func (r *recordingRenderer) EndScissorMode() { r.record(DrawCommand{Op: "EndScissorMode"}) }
+
+func (r *recordingRenderer) DrawRectangle(rect rl.Rectangle, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawRectangle", Rect: rect, Color: color})
+}
+
+func (r *recordingRenderer) DrawRectangleLines(rect rl.Rectangle, thick float32, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawRectangleLines", Rect: rect, Color: color})
+}
+
+func (r *recordingRenderer) DrawRectanglePro(rect rl.Rectangle, origin rl.Vector2, rotation float32, color rl.Color) {
+	r.record(DrawCommand{Op: fmt.Sprintf("DrawRectanglePro rotation=%.1f", rotation), Rect: rect, Color: color})
+}
+
+func (r *recordingRenderer) DrawCircle(center rl.Vector2, radius float32, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawCircle", Rect: rl.Rectangle{X: center.X, Y: center.Y, Width: radius}, Color: color})
+}
+
+func (r *recordingRenderer) DrawTriangle(a, b, c rl.Vector2, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawTriangle", Points: []rl.Vector3{{X: a.X, Y: a.Y}, {X: b.X, Y: b.Y}, {X: c.X, Y: c.Y}}, Color: color})
+}
+
+func (r *recordingRenderer) DrawText(text string, x, y, size int32, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawText", Text: text, Rect: rl.Rectangle{X: float32(x), Y: float32(y), Height: float32(size)}, Color: color})
+}
+
+func (r *recordingRenderer) DrawTexture(texture rl.Texture2D, source, dest rl.Rectangle, tint rl.Color) {
+	r.record(DrawCommand{Op: "DrawTexture", Rect: dest, Color: tint})
+}
+
+func (r *recordingRenderer) DrawMeshes(mesh rl.Mesh, material rl.Material, transforms []rl.Matrix) {
+	if len(transforms) == 0 {
+		return
+	}
+	var color rl.Color
+	if material.Maps != nil {
+		color = material.Maps.Color
+	}
+	r.record(DrawCommand{Op: "DrawMeshes", Points: instancePositions(transforms), Color: color})
+}
+
+func (r *recordingRenderer) DrawModel(model rl.Model, transforms []rl.Matrix, tint rl.Color) {
+	if len(transforms) == 0 {
+		return
+	}
+	color := tint
+	if materials := model.GetMaterials(); len(materials) > 0 && materials[0].Maps != nil {
+		color = rl.ColorTint(materials[0].Maps.Color, tint)
+	}
+	r.record(DrawCommand{Op: "DrawModel", Points: instancePositions(transforms), Color: color})
+}
+
+func (r *recordingRenderer) DrawSphere(center rl.Vector3, radius float32, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawSphere", Points: []rl.Vector3{center}, Color: color})
+}
+
+func (r *recordingRenderer) DrawCube(center, size rl.Vector3, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawCube", Points: []rl.Vector3{center, size}, Color: color})
+}
+
+func (r *recordingRenderer) DrawLine3D(start, end rl.Vector3, color rl.Color) {
+	r.record(DrawCommand{Op: "DrawLine3D", Points: []rl.Vector3{start, end}, Color: color})
+}
+
+func (r *recordingRenderer) DrawSky(center rl.Vector3, horizon, zenith rl.Color) {
+	r.record(DrawCommand{Op: "DrawSky", Points: []rl.Vector3{center}, Color: horizon})
+}
+
+// instancePositions returns the translation of each transform.
+func instancePositions(transforms []rl.Matrix) []rl.Vector3 {
+	positions := make([]rl.Vector3, len(transforms))
+	for i, m := range transforms {
+		positions[i] = rl.Vector3{X: m.M12, Y: m.M13, Z: m.M14}
+	}
+	return positions
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares recorded draw commands with testdata/name.golden.
+func checkGolden(t *testing.T, name string, r *recordingRenderer) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got := r.String()
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("draw commands differ from %s; run with -update and review the diff\ngot:\n%s", path, got)
+	}
+}
+
+// keep puts each of the given globals back to its current value when the test ends.
+func keep(t *testing.T, globals ...any) {
+	for _, g := range globals {
+		v := reflect.ValueOf(g).Elem()
+		saved := reflect.New(v.Type()).Elem()
+		saved.Set(v)
+		t.Cleanup(func() { v.Set(saved) })
+	}
+}
+
+// startHeadless starts a fixed world with the car at the spawn point, drawn to
+// a recording renderer of the given size. The game state it and the test
+// change is put back when the test ends, so tests can run in any order.
+func startHeadless(t *testing.T, width, height float32) *recordingRenderer {
+	keep(t, &renderer, &headless, &currentState, &showSettingsOverlay, &uiScale,
+		&worldSeed, &discovered, &chunks, &lastPlayerChunk, &car, &trafficCars, &pedestrians,
+		&odometer, &trips, &shownTrip, &showFPSCounter, &showSpeedKmh, &useMph,
+		&minimapZoom, &minimapNorthUp, &currentWeather, &notification, &notificationTimer,
+		&raceState, &activeMission, &missionsTaken, &cash,
+		&wanted, &policeCars, &roadblocks, &hideTimer, &arrestTimer,
+		&collected, &collectTotals)
+	r := newRecordingRenderer(width, height)
+	initHeadless(r)
+	worldSeed = 42
+	discovered = map[Coord]discoveredChunk{}
+	initCar()
+	initWorld()
+	currentState = Playing
+	return r
+}
+
+func TestDrawHUDGolden(t *testing.T) {
+	for _, size := range []struct {
+		name          string
+		width, height float32
+	}{{"hud_800x600", 800, 600}, {"hud_1600x900", 1600, 900}} {
+		t.Run(size.name, func(t *testing.T) {
+			r := startHeadless(t, size.width, size.height)
+			car.speed, car.fuel, car.damage = 25, 0.8, 12
+			showFPSCounter, showSpeedKmh, useMph = false, true, false
+			shownTrip, odometer = 0, 12345
+			trips[0] = TripStats{Distance: 2500, Time: 150, TopSpeed: 40, Collisions: 1}
+			trips[0].BiomeTime[Highway] = 150
+			cash, wanted, hideTimer = 120, 2, 0
+			collectTotals[Highway].Coins = 3
+			currentWeather = WeatherClear
+			minimapZoom, minimapNorthUp = 0, false
+			notify("Saved to Slot 1")
+
+			r.Reset()
+			drawHUD()
+			checkGolden(t, size.name, r)
+		})
+	}
+}
+
+func TestDrawWorldGolden(t *testing.T) {
+	r := startHeadless(t, 800, 600)
+	r.Reset()
+	drawWorld()
+	checkGolden(t, "world", r)
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Directory holding save slots, relative to the working directory.
+const SAVE_DIR = "saves"
+
+// Single save file written by older builds (version 1); still loaded by Continue.
+const LEGACY_SAVE_FILE = "drive3d_save.json"
+
+// Version of the save format written by this build.
+//
+//	1: discovered chunks only
+//	2: seed, car state, play time and stats
+//	3: time of day
+//	4: collected coins and tokens
+//	5: landmarks
+const SAVE_VERSION = 5
+
+// Slot written automatically every AUTOSAVE_INTERVAL seconds of play and on exit.
+const AUTOSAVE_SLOT = "autosave"
+
+const AUTOSAVE_INTERVAL float32 = 120
+
+// All save slots in the order they are listed on the slot screen.
+var saveSlots = []string{AUTOSAVE_SLOT, "slot1", "slot2", "slot3"}
+
+// SaveData is the on-disk save game.
+type SaveData struct {
+	Version    int          `json:"version"`
+	SavedAt    time.Time    `json:"savedAt"`
+	Seed       int64        `json:"seed"`
+	PlayTime   float32      `json:"playTime"`
+	TimeOfDay  float32      `json:"timeOfDay"`
+	Car        savedCar     `json:"car"`
+	Discovered []savedChunk `json:"discovered"`
+	Stats      savedStats   `json:"stats"`
+	// Picked-up collectibles, so they stay gone, and totals by chunk type.
+	Collected    []savedCollected `json:"collected"`
+	CollectTotal [6]collectTotal  `json:"collectTotal"`
+}
+
+// savedChunk is a discovered chunk as stored in the save file.
+type savedChunk struct {
+	X        int `json:"x"`
+	Y        int `json:"y"`
+	Type     int `json:"type"`
+	RoadType int `json:"roadType"`
+	Landmark int `json:"landmark,omitempty"`
+}
+
+// savedCollected lists the collectibles picked up in a chunk by index.
+type savedCollected struct {
+	X     int   `json:"x"`
+	Y     int   `json:"y"`
+	Items []int `json:"items"`
+}
+
+// savedCar is the persistent part of the car's state.
+type savedCar struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Z      float32 `json:"z"`
+	Yaw    float32 `json:"yaw"`
+	Speed  float32 `json:"speed"`
+	Damage float32 `json:"damage"`
+	Fuel   float32 `json:"fuel"`
+}
+
+// savedStats holds the trip computer and earnings.
+type savedStats struct {
+	Odometer float32      `json:"odometer"`
+	Trips    [2]TripStats `json:"trips"`
+	Cash     float32      `json:"cash"`
+}
+
+var (
+	// Seconds played in the current session, including loaded play time.
+	playTime float32
+	// A world is loaded and should be autosaved.
+	sessionActive bool
+	autosaveTimer float32
+	autosaveDue   bool
+	// Screen capture used as the thumbnail of the next save.
+	sessionThumbnail *rl.Image
+	thumbnailWanted  bool
+)
+
+// slotPath returns the JSON file of a save slot.
+func slotPath(slot string) string {
+	return filepath.Join(SAVE_DIR, slot+".json")
+}
+
+// thumbnailPath returns the PNG thumbnail of a save slot.
+func thumbnailPath(slot string) string {
+	return filepath.Join(SAVE_DIR, slot+".png")
+}
+
+// slotTitle is the display name of a slot.
+func slotTitle(slot string) string {
+	if slot == AUTOSAVE_SLOT {
+		return "Autosave"
+	}
+	return "Slot " + slot[len(slot)-1:]
+}
+
+// captureThumbnail keeps a small copy of the frame drawn so far. It is called
+// after the 3D scene and before the HUD so thumbnails show only the world.
+func captureThumbnail() {
+	if !thumbnailWanted {
+		return
+	}
+	thumbnailWanted = false
+	if sessionThumbnail != nil {
+		rl.UnloadImage(sessionThumbnail)
+	}
+	sessionThumbnail = rl.LoadImageFromScreen()
+	rl.ImageResize(sessionThumbnail, 320, 180)
+}
+
+// updateAutosave counts play time and autosaves periodically.
+func updateAutosave() {
+	dt := rl.GetFrameTime()
+	playTime += dt
+	// The thumbnail is captured during the frame after the autosave is scheduled.
+	if autosaveDue && !thumbnailWanted {
+		autosaveDue = false
+		if err := writeSave(AUTOSAVE_SLOT); err != nil {
+			rl.TraceLog(rl.LogWarning, "autosave failed: %v", err)
+		}
+	}
+	autosaveTimer += dt
+	if autosaveTimer >= AUTOSAVE_INTERVAL {
+		autosaveTimer = 0
+		autosaveDue = true
+		thumbnailWanted = true
+	}
+}
+
+// writeSave stores the current session in the given slot.
+func writeSave(slot string) error {
+	data := SaveData{
+		Version:   SAVE_VERSION,
+		SavedAt:   time.Now(),
+		Seed:      worldSeed,
+		PlayTime:  playTime,
+		TimeOfDay: timeOfDay,
+		Car: savedCar{
+			X: car.position.X, Y: car.position.Y, Z: car.position.Z,
+			Yaw: car.yaw, Speed: car.speed, Damage: car.damage, Fuel: car.fuel,
+		},
+		Stats:        savedStats{Odometer: odometer, Trips: trips, Cash: cash},
+		CollectTotal: collectTotals,
+	}
+	for coord, seen := range discovered {
+		data.Discovered = append(data.Discovered, savedChunk{X: coord.X, Y: coord.Y, Type: seen.Type, RoadType: seen.RoadType, Landmark: seen.Landmark})
+	}
+	for coord, items := range collected {
+		saved := savedCollected{X: coord.X, Y: coord.Y}
+		for index := range items {
+			saved.Items = append(saved.Items, index)
+		}
+		sort.Ints(saved.Items)
+		data.Collected = append(data.Collected, saved)
+	}
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(SAVE_DIR, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(slotPath(slot), bytes, 0o644); err != nil {
+		return err
+	}
+	if sessionThumbnail != nil {
+		if !rl.ExportImage(*sessionThumbnail, thumbnailPath(slot)) {
+			rl.TraceLog(rl.LogWarning, "could not write thumbnail for %s", slotTitle(slot))
+		}
+	}
+	// Achievement progress is saved with every game, but to the shared profile.
+	if err := writeProfile(); err != nil {
+		return err
+	}
+	notify("Saved to " + slotTitle(slot))
+	return nil
+}
+
+// readSave loads and migrates a save file to the current version.
+func readSave(path string) (*SaveData, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var data SaveData
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+	if data.Version > SAVE_VERSION {
+		return nil, fmt.Errorf("save version %d is newer than supported version %d", data.Version, SAVE_VERSION)
+	}
+	migrateSave(&data)
+	return &data, nil
+}
+
+// migrateSave upgrades older save versions in place.
+func migrateSave(data *SaveData) {
+	if data.Version < 2 {
+		// Version 1 only tracked discovery: start a fresh car at the origin.
+		data.Seed = time.Now().UnixNano()
+		data.Car = savedCar{X: CHUNK_SIZE / 2, Z: CHUNK_SIZE / 2, Fuel: 1}
+	}
+	if data.Version < 3 {
+		data.TimeOfDay = 10
+	}
+	if data.Version < 4 {
+		// Collectibles didn't exist yet: every one is still out there.
+		data.Collected = nil
+		data.CollectTotal = [6]collectTotal{}
+	}
+	if data.Version < 5 {
+		// Landmarks didn't exist yet: no discovered chunk holds one.
+		for i := range data.Discovered {
+			data.Discovered[i].Landmark = LandmarkNone
+		}
+	}
+	data.Version = SAVE_VERSION
+}
+
+// continueSavePath returns the save used by "Continue", or "" if there is none.
+func continueSavePath() string {
+	for _, path := range []string{slotPath(AUTOSAVE_SLOT), LEGACY_SAVE_FILE} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// loadSave replaces the current session with the one stored at path.
+func loadSave(path string) error {
+	data, err := readSave(path)
+	if err != nil {
+		return err
+	}
+	worldSeed = data.Seed
+	playTime = data.PlayTime
+	timeOfDay = data.TimeOfDay
+	odometer = data.Stats.Odometer
+	trips = data.Stats.Trips
+	cash = data.Stats.Cash
+	collectTotals = data.CollectTotal
+	collected = make(map[Coord]map[int]bool, len(data.Collected))
+	for _, c := range data.Collected {
+		items := map[int]bool{}
+		for _, index := range c.Items {
+			items[index] = true
+		}
+		collected[Coord{c.X, c.Y}] = items
+	}
+	discovered = make(map[Coord]discoveredChunk, len(data.Discovered))
+	for _, c := range data.Discovered {
+		if c.Type < 0 || c.Type >= len(typeNames) || c.RoadType < 0 || c.RoadType >= len(roadColors) {
+			continue
+		}
+		if c.Landmark < 0 || c.Landmark >= len(landmarkDefs) {
+			c.Landmark = LandmarkNone
+		}
+		discovered[Coord{c.X, c.Y}] = discoveredChunk{Type: c.Type, RoadType: c.RoadType, Landmark: c.Landmark}
+	}
+	initCar()
+	car.position = rl.Vector3{X: data.Car.X, Y: data.Car.Y, Z: data.Car.Z}
+	car.yaw = data.Car.Yaw
+	car.speed = data.Car.Speed
+	car.damage = data.Car.Damage
+	car.fuel = data.Car.Fuel
+	initWorld()
+	startSession()
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Distance fog ends where chunk streaming does: the edge of the 5x5 window is
+// at least two chunks from the car, so nothing pops in inside the fog.
+const (
+	VIEW_RADIUS     float32 = 2 * CHUNK_SIZE
+	FOG_START_RATIO float32 = 0.45
+)
+
+// Radius of the sky dome around the camera; inside the far clip plane.
+const SKY_RADIUS = 500
+
+// Sky dome shader: a vertical gradient from horizon to zenith with a sun disc.
+const skyVS = `#version 330
+in vec3 vertexPosition;
+uniform mat4 mvp;
+out vec3 direction;
+void main() {
+    direction = vertexPosition;
+    gl_Position = mvp*vec4(vertexPosition, 1.0);
+}
+`
+
+const skyFS = `#version 330
+in vec3 direction;
+uniform vec3 horizon;
+uniform vec3 zenith;
+uniform vec3 sunDir;
+uniform vec3 sunColor;
+out vec4 finalColor;
+void main() {
+    vec3 d = normalize(direction);
+    vec3 color = mix(horizon, zenith, smoothstep(0.0, 0.5, d.y));
+    float sun = smoothstep(0.9985, 0.9995, dot(d, -sunDir));
+    finalColor = vec4(mix(color, sunColor, sun*step(0.0, d.y)), 1.0);
+}
+`
+
+// Daytime haze near the horizon for each chunk type.
+var biomeHaze = []rl.Color{
+	Highway:    rl.NewColor(190, 205, 220, 255),
+	City:       rl.NewColor(180, 185, 195, 255),
+	Commercial: rl.NewColor(185, 190, 200, 255),
+	Desert:     rl.NewColor(235, 215, 175, 255),
+	Forest:     rl.NewColor(175, 200, 185, 255),
+	Snow:       rl.NewColor(230, 236, 245, 255),
+}
+
+var (
+	// Haze of the biome around the car, eased when crossing chunk borders.
+	// Kept normalized so slow easing isn't lost to 8-bit rounding.
+	haze = rl.ColorNormalize(biomeHaze[Highway])
+
+	skyShader    rl.Shader
+	skyMesh      rl.Mesh
+	skyMaterial  rl.Material
+	skySupported bool
+	// Sky shader uniform locations, looked up by initSky.
+	skyHorizonLoc, skyZenithLoc, skySunDirLoc, skySunColorLoc int32
+)
+
+// initSky builds the sky dome; without shader support the clear color stands in.
+func initSky() {
+	skyShader = rl.LoadShaderFromMemory(skyVS, skyFS)
+	skySupported = skyShader.ID != rl.GetShaderIdDefault()
+	if !skySupported {
+		return
+	}
+	skyMesh = rl.GenMeshSphere(SKY_RADIUS, 16, 32)
+	skyMaterial = rl.LoadMaterialDefault()
+	skyMaterial.Shader = skyShader
+	loc := func(name string) int32 { return rl.GetShaderLocation(skyShader, name) }
+	skyHorizonLoc, skyZenithLoc = loc("horizon"), loc("zenith")
+	skySunDirLoc, skySunColorLoc = loc("sunDir"), loc("sunColor")
+}
+
+// updateSky eases the haze toward the biome the car is in.
+func updateSky() {
+	chunk := chunks[getChunkCoord(car.position)]
+	if chunk == nil {
+		return
+	}
+	target := rl.ColorNormalize(biomeHaze[chunk.Type])
+	t := smoothFactor(0.5, rl.GetFrameTime())
+	haze = rl.Vector4{
+		X: haze.X + (target.X-haze.X)*t,
+		Y: haze.Y + (target.Y-haze.Y)*t,
+		Z: haze.Z + (target.Z-haze.Z)*t,
+		W: 1,
+	}
+}
+
+// horizonColor is the sky at the horizon: the sky tinted by the biome's haze.
+func horizonColor() rl.Color {
+	return rl.ColorLerp(skyColor(), rl.ColorBrightness(rl.ColorFromNormalized(haze), -0.8*(1-daylight())), 0.6)
+}
+
+// zenithColor is the sky overhead, deeper than the horizon and washed out by weather.
+func zenithColor() rl.Color {
+	density, _ := weatherFog()
+	return rl.ColorLerp(rl.ColorBrightness(skyColor(), -0.3), fogColor(), rl.Clamp(density*30, 0, 1))
+}
+
+// drawSky draws the sky dome around the camera behind everything else.
+func drawSky() {
+	if !skySupported {
+		return
+	}
+	dir := sunDirection()
+	light := daylight()
+	rl.SetShaderValue(skyShader, skySunDirLoc, []float32{dir.X, dir.Y, dir.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(skyShader, skySunColorLoc, []float32{1, 0.95 * light, 0.8 * light}, rl.ShaderUniformVec3)
+	renderer.DrawSky(camera.Position, fogColor(), zenithColor())
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// slotInfo is what the slot screen shows about one save slot.
+type slotInfo struct {
+	slot      string
+	data      *SaveData // nil when the slot is empty or unreadable
+	err       error
+	thumbnail rl.Texture2D
+}
+
+var (
+	slotInfos []slotInfo
+	// The slot screen writes to the chosen slot instead of loading it.
+	slotScreenSaving bool
+	// State to return to when the slot screen is closed.
+	slotScreenReturn GameState
+)
+
+// openSlotScreen lists the save slots for saving or loading.
+func openSlotScreen(saving bool) {
+	slotScreenSaving = saving
+	slotScreenReturn = currentState
+	slotInfos = nil
+	for _, slot := range saveSlots {
+		info := slotInfo{slot: slot}
+		info.data, info.err = readSave(slotPath(slot))
+		if _, err := os.Stat(thumbnailPath(slot)); info.data != nil && err == nil {
+			info.thumbnail = rl.LoadTexture(thumbnailPath(slot))
+		}
+		slotInfos = append(slotInfos, info)
+	}
+	currentState = SlotScreen
+}
+
+// closeSlotScreen frees the thumbnails and switches to the given state.
+func closeSlotScreen(next GameState) {
+	for _, info := range slotInfos {
+		if info.thumbnail.ID != 0 {
+			rl.UnloadTexture(info.thumbnail)
+		}
+	}
+	slotInfos = nil
+	currentState = next
+}
+
+// slotRect returns the bounds of the i-th slot row.
+func slotRect(i int) rl.Rectangle {
+	return uiRect(AnchorCenter, 0, float32(-165+105*i), 500, 100)
+}
+
+// slotBackRect returns the "Back" button of the slot screen.
+func slotBackRect() rl.Rectangle {
+	return uiRect(AnchorCenter, 0, 255, 200, 40)
+}
+
+// slotSelectable reports whether the slot can be clicked in the current mode.
+func slotSelectable(info slotInfo) bool {
+	if slotScreenSaving {
+		return info.slot != AUTOSAVE_SLOT
+	}
+	return info.data != nil
+}
+
+func updateSlotScreen() {
+	if uiClicked(slotBackRect()) {
+		closeSlotScreen(slotScreenReturn)
+		return
+	}
+	for i, info := range slotInfos {
+		if !slotSelectable(info) || !uiClicked(slotRect(i)) {
+			continue
+		}
+		if slotScreenSaving {
+			if err := writeSave(info.slot); err != nil {
+				rl.TraceLog(rl.LogWarning, "could not save game: %v", err)
+			}
+			closeSlotScreen(Playing)
+			showSettingsOverlay = false
+		} else {
+			closeSlotScreen(Playing)
+			if err := loadSave(slotPath(info.slot)); err != nil {
+				rl.TraceLog(rl.LogWarning, "could not load save: %v", err)
+				currentState = Menu
+			}
+		}
+		return
+	}
+}
+
+func drawSlotScreen() {
+	renderer.ClearBackground(rl.RayWhite)
+	title := "Load Game"
+	if slotScreenSaving {
+		title = "Save Game"
+	}
+	header := uiRect(AnchorTopLeft, 0, 30, 0, 30)
+	header.Width, _ = renderer.ScreenSize()
+	drawUITextCentered(title, header, 0, 30, rl.Black)
+
+	for i, info := range slotInfos {
+		rect := slotRect(i)
+		color := rl.Gray
+		if !slotSelectable(info) {
+			color = rl.LightGray
+		}
+		renderer.DrawRectangle(rect, color)
+
+		// Thumbnail on the left, metadata on the right.
+		thumb := rl.Rectangle{X: rect.X + ui(5), Y: rect.Y + ui(5), Width: ui(160), Height: ui(90)}
+		if info.thumbnail.ID != 0 {
+			src := rl.Rectangle{Width: float32(info.thumbnail.Width), Height: float32(info.thumbnail.Height)}
+			renderer.DrawTexture(info.thumbnail, src, thumb, rl.White)
+		} else {
+			renderer.DrawRectangle(thumb, rl.DarkGray)
+		}
+		textX := thumb.X + thumb.Width + ui(10)
+		drawUIText(slotTitle(info.slot), textX, rect.Y+ui(5), 20, rl.Black)
+		var lines []string
+		switch {
+		case info.data != nil:
+			d := info.data
+			biome := "Unknown"
+			if seen, ok := discoveredAt(d, getChunkCoord(rl.Vector3{X: d.Car.X, Z: d.Car.Z})); ok && seen.Type < len(typeNames) {
+				biome = typeNames[seen.Type]
+			}
+			lines = []string{
+				d.SavedAt.Format("2006-01-02 15:04"),
+				fmt.Sprintf("Played %s  Odometer %s", formatDuration(d.PlayTime), formatDistance(d.Stats.Odometer)),
+				fmt.Sprintf("%s  Damage %.0f%%  Fuel %.0f%%", biome, d.Car.Damage, d.Car.Fuel*100),
+			}
+		case info.err != nil && !errors.Is(info.err, os.ErrNotExist):
+			lines = []string{"Unreadable save"}
+		default:
+			lines = []string{"Empty"}
+		}
+		for k, line := range lines {
+			drawUIText(line, textX, rect.Y+ui(float32(32+20*k)), 16, rl.Black)
+		}
+	}
+
+	drawUIButton(slotBackRect(), "Back", 20)
+}
+
+// discoveredAt looks up a chunk in a save's discovered list.
+func discoveredAt(data *SaveData, coord Coord) (discoveredChunk, bool) {
+	for _, c := range data.Discovered {
+		if c.X == coord.X && c.Y == coord.Y {
+			return discoveredChunk{Type: c.Type, RoadType: c.RoadType, Landmark: c.Landmark}, true
+		}
+	}
+	return discoveredChunk{}, false
+}
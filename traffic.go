@@ -0,0 +1,326 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Lane centers are this far from a road's centerline; traffic keeps right.
+const LANE_OFFSET float32 = ROAD_WIDTH / 4
+
+// Distance traffic keeps to whatever is ahead in its lane, in meters.
+const TRAFFIC_GAP float32 = 6
+
+// Traffic and the player's car touch when their centers are this close.
+const TRAFFIC_HIT_RADIUS float32 = 2
+
+// Average number of traffic cars spawned in a chunk, by chunk type.
+var trafficDensity = []float32{Highway: 2, City: 3, Commercial: 2, Desert: 0.5, Forest: 0.7, Snow: 0.4}
+
+// Cruising speed in m/s, by chunk type.
+var trafficSpeed = []float32{Highway: 20, City: 11, Commercial: 11, Desert: 16, Forest: 12, Snow: 9}
+
+var trafficColors = []rl.Color{rl.Orange, rl.SkyBlue, rl.Beige, rl.Maroon, rl.DarkGreen, rl.Gold}
+
+// Travel directions: east, south, west, north. Turning right adds one.
+var trafficDirs = []rl.Vector3{{X: 1}, {Z: 1}, {X: -1}, {Z: -1}}
+
+// trafficCar is an AI vehicle following the road network.
+type trafficCar struct {
+	Position rl.Vector3
+	Dir      int     // index into trafficDirs
+	Line     float32 // centerline of the road followed: Z when driving along X, X otherwise
+	Speed    float32
+	Yaw      float32 // drawn heading, eased through turns
+	Color    int
+
+	// Turn decided for the intersection ahead (-1 for straight on).
+	hasPlan  bool
+	turnTo   int
+	turnAt   float32 // coordinate along the travel axis where the turn happens
+	turnLine float32 // centerline of the road turned onto
+	// Center of the last intersection planned, so it isn't planned again after turning.
+	lastCrossing rl.Vector3
+}
+
+var (
+	trafficCars []trafficCar
+	// Shared model; tinted per car unless it comes from the asset manifest.
+	trafficModel  rl.Model
+	trafficTinted bool
+)
+
+// initTraffic loads the traffic car model.
+func initTraffic() {
+	var asset bool
+	trafficModel, asset = loadModelOrPrimitive(manifest.Vehicles["traffic"], func() rl.Mesh { return rl.GenMeshCube(1, 0.5, 2) })
+	trafficTinted = !asset
+}
+
+// alongAxis returns the coordinate of v along the axis of travel direction dir.
+func alongAxis(v rl.Vector3, dir int) float32 {
+	if dir%2 == 0 {
+		return v.X
+	}
+	return v.Z
+}
+
+// setAcrossAxis places v in the right-hand lane of the road with centerline line.
+func setAcrossAxis(v *rl.Vector3, dir int, line float32) {
+	right := trafficDirs[(dir+1)%4]
+	if dir%2 == 0 {
+		v.Z = line + right.Z*LANE_OFFSET
+	} else {
+		v.X = line + right.X*LANE_OFFSET
+	}
+}
+
+// roadAt reports whether the world position (x, z) is on a road of a loaded chunk.
+func roadAt(x, z float32) bool {
+	chunk := chunks[getChunkCoord(rl.Vector3{X: x, Z: z})]
+	if chunk == nil {
+		return false
+	}
+	for _, road := range chunkRoads(chunk) {
+		if float32(math.Abs(float64(x-road.X))) <= road.Width/2 && float32(math.Abs(float64(z-road.Z))) <= road.Length/2 {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnChunkTraffic places traffic on the roads of a newly built chunk.
+func spawnChunkTraffic(chunk *Chunk) {
+	density := trafficDensity[chunk.Type]
+	count := int(density)
+	if rand.Float32() < density-float32(count) {
+		count++
+	}
+	roads := chunkRoads(chunk)
+	for k := 0; k < count; k++ {
+		road := roads[rand.Intn(len(roads))]
+		t := trafficCar{Color: rand.Intn(len(trafficColors))}
+		if road.Width == CHUNK_SIZE {
+			t.Dir, t.Line = 2*rand.Intn(2), road.Z
+			t.Position.X = road.X + (rand.Float32()-0.5)*road.Width*0.8
+		} else {
+			t.Dir, t.Line = 1+2*rand.Intn(2), road.X
+			t.Position.Z = road.Z + (rand.Float32()-0.5)*road.Length*0.8
+		}
+		setAcrossAxis(&t.Position, t.Dir, t.Line)
+		if rl.Vector3Distance(t.Position, car.position) < 15 || trafficNear(t.Position, 8) {
+			continue
+		}
+		d := trafficDirs[t.Dir]
+		t.Yaw = float32(math.Atan2(float64(d.Z), float64(d.X)))
+		t.Speed = trafficSpeed[chunk.Type]
+		trafficCars = append(trafficCars, t)
+	}
+}
+
+// despawnChunkTraffic removes the traffic inside an unloaded chunk.
+func despawnChunkTraffic(coord Coord) {
+	kept := trafficCars[:0]
+	for _, t := range trafficCars {
+		if getChunkCoord(t.Position) != coord {
+			kept = append(kept, t)
+		}
+	}
+	trafficCars = kept
+}
+
+// trafficNear reports whether a traffic car is within distance of pos.
+func trafficNear(pos rl.Vector3, distance float32) bool {
+	for _, t := range trafficCars {
+		if rl.Vector3Distance(t.Position, pos) < distance {
+			return true
+		}
+	}
+	return false
+}
+
+// closesIn reports whether a move from one position to another ends within
+// TRAFFIC_HIT_RADIUS of other and nearer to it than before. Moving apart is
+// always allowed, so two cars that end up touching can separate.
+func closesIn(from, to, other rl.Vector3) bool {
+	distance := rl.Vector3Distance(to, other)
+	return distance < TRAFFIC_HIT_RADIUS && distance < rl.Vector3Distance(from, other)
+}
+
+// trafficCollision reports whether the player's car moving from one position
+// to another would run into traffic.
+func trafficCollision(from, to rl.Vector3) bool {
+	for _, t := range trafficCars {
+		if closesIn(from, to, t.Position) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateTraffic drives every traffic car along its lane.
+func updateTraffic() {
+	dt := rl.GetFrameTime()
+	for i := range trafficCars {
+		updateTrafficCar(&trafficCars[i], i, dt)
+	}
+}
+
+// updateTrafficCar keeps distance to whatever is ahead, picks a way through
+// intersections and turns around at dead ends.
+func updateTrafficCar(t *trafficCar, index int, dt float32) {
+	chunk := chunks[getChunkCoord(t.Position)]
+	if chunk == nil {
+		return
+	}
+	d := trafficDirs[t.Dir]
+	sign := alongAxis(d, t.Dir)
+
+	// Slow for turns and for anything in the lane ahead, including the player and pedestrians.
+	target := trafficSpeed[chunk.Type]
+	if t.hasPlan && t.turnTo >= 0 {
+		target = float32(math.Min(float64(target), 6))
+	}
+	gap := float32(math.MaxFloat32)
+	ahead := func(pos rl.Vector3) {
+		rel := rl.Vector3Subtract(pos, t.Position)
+		forward := rl.Vector3DotProduct(rel, d)
+		lateral := float32(math.Abs(float64(rl.Vector3DotProduct(rel, trafficDirs[(t.Dir+1)%4]))))
+		if forward > 0 && forward < 30 && lateral < 2 && forward < gap {
+			gap = forward
+		}
+	}
+	ahead(car.position)
+	for j := range trafficCars {
+		if j != index {
+			ahead(trafficCars[j].Position)
+		}
+	}
+	for _, p := range pedestrians {
+		ahead(p.Position)
+	}
+	target = float32(math.Min(float64(target), math.Max(0, float64((gap-TRAFFIC_GAP)*1.5))))
+	if t.Speed < target {
+		t.Speed = float32(math.Min(float64(target), float64(t.Speed+4*dt)))
+	} else {
+		t.Speed = float32(math.Max(float64(target), float64(t.Speed-12*dt)))
+	}
+
+	if !t.hasPlan {
+		planTrafficTurn(t, chunk)
+	}
+	next, dir, line := rl.Vector3Add(t.Position, rl.Vector3Scale(d, t.Speed*dt)), t.Dir, t.Line
+	turning := t.hasPlan && sign*alongAxis(next, t.Dir) >= sign*t.turnAt
+	if turning && t.turnTo >= 0 {
+		dir, line = t.turnTo, t.turnLine
+		setAcrossAxis(&next, dir, line)
+	} else if !t.hasPlan {
+		// Dead end ahead with no intersection to turn at: swap to the other lane.
+		lookahead := rl.Vector3Add(next, rl.Vector3Scale(d, 3))
+		if !roadAt(lookahead.X, lookahead.Z) {
+			dir = (t.Dir + 2) % 4
+			setAcrossAxis(&next, dir, line)
+		}
+	}
+
+	// Moves, turns and lane swaps wait while they would run into the player.
+	if closesIn(t.Position, next, car.position) {
+		t.Speed = 0
+	} else {
+		t.Position, t.Dir, t.Line = next, dir, line
+		if turning {
+			t.hasPlan = false
+		}
+	}
+
+	heading := trafficDirs[t.Dir]
+	diff := math.Remainder(math.Atan2(float64(heading.Z), float64(heading.X))-float64(t.Yaw), 2*math.Pi)
+	t.Yaw += float32(diff) * smoothFactor(6, dt)
+}
+
+// planTrafficTurn looks for a crossing road just ahead and chooses whether to
+// go straight on, turn left or turn right there, among the ways that exist.
+func planTrafficTurn(t *trafficCar, chunk *Chunk) {
+	d := trafficDirs[t.Dir]
+	sign := alongAxis(d, t.Dir)
+	position := alongAxis(t.Position, t.Dir)
+	lookahead := rl.Vector3Add(t.Position, rl.Vector3Scale(d, ROAD_WIDTH*2))
+	candidates := append(chunkRoads(chunk), roadsOf(getChunkCoord(lookahead))...)
+	for _, road := range candidates {
+		// Crossing roads run across the travel axis and span this road's centerline.
+		var crossing, span, spanCenter float32
+		if t.Dir%2 == 0 {
+			if road.Width == CHUNK_SIZE {
+				continue
+			}
+			crossing, span, spanCenter = road.X, road.Length, road.Z
+		} else {
+			if road.Length == CHUNK_SIZE {
+				continue
+			}
+			crossing, span, spanCenter = road.Z, road.Width, road.X
+		}
+		distance := sign * (crossing - position)
+		if distance < LANE_OFFSET || distance > ROAD_WIDTH*2 || math.Abs(float64(t.Line-spanCenter)) > float64(span/2) {
+			continue
+		}
+		center := t.Position
+		if t.Dir%2 == 0 {
+			center.X, center.Z = crossing, t.Line
+		} else {
+			center.X, center.Z = t.Line, crossing
+		}
+		if center == t.lastCrossing {
+			continue
+		}
+		var options []int
+		for _, turn := range []int{-1, -1, 1, 3} { // straight on is twice as likely
+			dir := t.Dir
+			if turn >= 0 {
+				dir = (t.Dir + turn) % 4
+			}
+			probe := rl.Vector3Add(center, rl.Vector3Scale(trafficDirs[dir], ROAD_WIDTH))
+			if roadAt(probe.X, probe.Z) {
+				options = append(options, turn)
+			}
+		}
+		t.hasPlan, t.lastCrossing, t.turnTo = true, center, -1
+		t.turnAt, t.turnLine = crossing+sign*ROAD_WIDTH, crossing
+		if len(options) == 0 {
+			return
+		}
+		switch options[rand.Intn(len(options))] {
+		case 1: // right: start turning before the crossing's centerline
+			t.turnTo, t.turnAt = (t.Dir+1)%4, crossing-sign*LANE_OFFSET
+		case 3: // left: after it
+			t.turnTo, t.turnAt = (t.Dir+3)%4, crossing+sign*LANE_OFFSET
+		}
+		return
+	}
+}
+
+// roadsOf returns the roads of a loaded chunk.
+func roadsOf(coord Coord) []roadStrip {
+	if chunk := chunks[coord]; chunk != nil {
+		return chunkRoads(chunk)
+	}
+	return nil
+}
+
+// drawTraffic draws the traffic cars, one batch per color.
+func drawTraffic() {
+	batches := make([][]rl.Matrix, len(trafficColors))
+	for _, t := range trafficCars {
+		transform := rl.MatrixMultiply(rl.MatrixRotateY(math.Pi/2-t.Yaw), rl.MatrixTranslate(t.Position.X, t.Position.Y, t.Position.Z))
+		batches[t.Color] = append(batches[t.Color], transform)
+	}
+	for i, transforms := range batches {
+		tint := rl.White
+		if trafficTinted {
+			tint = trafficColors[i]
+		}
+		renderer.DrawModel(trafficModel, transforms, tint)
+	}
+}
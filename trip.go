@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TripStats accumulates driving statistics since the last reset.
+type TripStats struct {
+	Distance   float32    // meters
+	Time       float32    // seconds
+	TopSpeed   float32    // m/s
+	BiomeTime  [6]float32 // seconds spent in each chunk type
+	Collisions int
+}
+
+var (
+	// Total distance driven in meters; never reset.
+	odometer float32
+	// Two independent trip meters, A and B.
+	trips [2]TripStats
+	// Which trip the trip computer shows: -1 hides it.
+	shownTrip = -1
+	// Display speeds and distances in imperial units.
+	useMph bool = false
+)
+
+// updateTrip records a frame of driving in which the car moved dist meters.
+func updateTrip(dist, dt float32) {
+	odometer += dist
+	speed := car.speed
+	if speed < 0 {
+		speed = -speed
+	}
+	chunkType := -1
+	if chunk := chunks[getChunkCoord(car.position)]; chunk != nil {
+		chunkType = chunk.Type
+	}
+	for i := range trips {
+		t := &trips[i]
+		t.Distance += dist
+		t.Time += dt
+		if speed > t.TopSpeed {
+			t.TopSpeed = speed
+		}
+		if chunkType >= 0 {
+			t.BiomeTime[chunkType] += dt
+		}
+	}
+}
+
+// updateTripComputer handles the trip computer keys.
+func updateTripComputer() {
+	// T cycles Trip A, Trip B and hidden; Backspace resets the trip on display.
+	if rl.IsKeyPressed(rl.KeyT) {
+		shownTrip++
+		if shownTrip >= len(trips) {
+			shownTrip = -1
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && shownTrip >= 0 {
+		trips[shownTrip] = TripStats{}
+	}
+}
+
+// recordCollision counts a crash on every trip meter.
+func recordCollision() {
+	for i := range trips {
+		trips[i].Collisions++
+	}
+}
+
+// formatSpeed formats a speed in m/s using the selected units.
+func formatSpeed(mps float32) string {
+	if useMph {
+		return fmt.Sprintf("%.0f mph", mps*2.23694)
+	}
+	return fmt.Sprintf("%.0f km/h", mps*3.6)
+}
+
+// formatDistance formats a distance in meters using the selected units.
+func formatDistance(m float32) string {
+	if useMph {
+		return fmt.Sprintf("%.2f mi", m/1609.344)
+	}
+	return fmt.Sprintf("%.2f km", m/1000)
+}
+
+// formatDuration formats seconds as m:ss.
+func formatDuration(s float32) string {
+	total := int(s)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+// drawTripComputer draws the selected trip meter below the gear icon.
+func drawTripComputer() {
+	if shownTrip < 0 {
+		return
+	}
+	t := trips[shownTrip]
+	var avg float32
+	if t.Time > 0 {
+		avg = t.Distance / t.Time
+	}
+	lines := []string{
+		fmt.Sprintf("Trip %c  (Backspace: reset)", 'A'+shownTrip),
+		"Odometer: " + formatDistance(odometer),
+		"Trip: " + formatDistance(t.Distance),
+		"Time: " + formatDuration(t.Time),
+		"Top speed: " + formatSpeed(t.TopSpeed),
+		"Avg speed: " + formatSpeed(avg),
+		fmt.Sprintf("Collisions: %d", t.Collisions),
+	}
+	for i, name := range typeNames {
+		if t.BiomeTime[i] > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, formatDuration(t.BiomeTime[i])))
+		}
+	}
+	panel := uiRect(AnchorTopLeft, 10, 60, 260, float32(10+20*len(lines)))
+	renderer.DrawRectangle(panel, rl.Fade(rl.LightGray, 0.8))
+	for i, line := range lines {
+		drawUIText(line, panel.X+ui(5), panel.Y+ui(float32(5+20*i)), 18, rl.Black)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The HUD is laid out for this window size and scaled to the actual one.
+const (
+	UI_REFERENCE_WIDTH  = 800
+	UI_REFERENCE_HEIGHT = 600
+)
+
+// anchor is the screen point a UI element is positioned relative to.
+type anchor int
+
+const (
+	AnchorTopLeft anchor = iota
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+	AnchorCenter
+)
+
+var (
+	// User-selectable multipliers applied on top of the automatic scale.
+	uiScaleOptions = []float32{0.75, 1.0, 1.25, 1.5, 2.0}
+	uiScaleIndex   = 1
+	// Current scale from UI units to screen pixels; see updateUIScale.
+	uiScale float32 = 1
+	// Short status line such as "Saved to Slot 1", shown at the top of the HUD.
+	notification      string
+	notificationTimer float32
+)
+
+// updateUIScale fits the reference layout into the window and applies the user's scale.
+// With FlagWindowHighdpi raylib reports logical pixels, so DPI is already accounted for.
+func updateUIScale() {
+	screenW, screenH := renderer.ScreenSize()
+	fit := float32(math.Min(float64(screenW/UI_REFERENCE_WIDTH), float64(screenH/UI_REFERENCE_HEIGHT)))
+	uiScale = rl.Clamp(fit*uiScaleOptions[uiScaleIndex], 0.5, 4)
+}
+
+// cycleUIScale selects the next user scale option.
+func cycleUIScale() {
+	uiScaleIndex = (uiScaleIndex + 1) % len(uiScaleOptions)
+	updateUIScale()
+}
+
+// uiScaleLabel describes the user scale for the settings overlay.
+func uiScaleLabel() string {
+	return fmt.Sprintf("UI Scale: %.0f%%", uiScaleOptions[uiScaleIndex]*100)
+}
+
+// ui converts a length in UI units to screen pixels.
+func ui(v float32) float32 {
+	return v * uiScale
+}
+
+// uiFont converts a font size in UI units to pixels.
+func uiFont(size int32) int32 {
+	return int32(float32(size)*uiScale + 0.5)
+}
+
+// uiRect places a w×h box (in UI units) at offset (x,y) from the given anchor.
+// For right/bottom anchors the offset is measured from the right/bottom edge to
+// the box's right/bottom edge; for AnchorCenter it offsets the box's center.
+func uiRect(a anchor, x, y, w, h float32) rl.Rectangle {
+	screenW, screenH := renderer.ScreenSize()
+	rect := rl.Rectangle{Width: ui(w), Height: ui(h)}
+	switch a {
+	case AnchorTopLeft:
+		rect.X, rect.Y = ui(x), ui(y)
+	case AnchorTopRight:
+		rect.X, rect.Y = screenW-ui(x)-rect.Width, ui(y)
+	case AnchorBottomLeft:
+		rect.X, rect.Y = ui(x), screenH-ui(y)-rect.Height
+	case AnchorBottomRight:
+		rect.X, rect.Y = screenW-ui(x)-rect.Width, screenH-ui(y)-rect.Height
+	case AnchorCenter:
+		rect.X, rect.Y = (screenW-rect.Width)/2+ui(x), (screenH-rect.Height)/2+ui(y)
+	}
+	return rect
+}
+
+// drawUIText draws text at a screen position with a font size in UI units.
+func drawUIText(text string, x, y float32, size int32, color rl.Color) {
+	renderer.DrawText(text, int32(x), int32(y), uiFont(size), color)
+}
+
+// drawUITextCentered draws text centered horizontally inside rect at offset y (UI units).
+func drawUITextCentered(text string, rect rl.Rectangle, y float32, size int32, color rl.Color) {
+	width := float32(renderer.MeasureText(text, uiFont(size)))
+	drawUIText(text, rect.X+(rect.Width-width)/2, rect.Y+ui(y), size, color)
+}
+
+// drawUIButton draws a gray button with a centered label.
+func drawUIButton(rect rl.Rectangle, label string, size int32) {
+	renderer.DrawRectangle(rect, rl.Gray)
+	drawUITextCentered(label, rect, (rect.Height/uiScale-float32(size))/2, size, rl.Black)
+}
+
+// notify shows a short message at the top of the HUD for two seconds.
+func notify(text string) {
+	notification = text
+	notificationTimer = 2
+}
+
+// drawNotification draws the latest message while it is still fresh.
+func drawNotification() {
+	if notificationTimer <= 0 {
+		return
+	}
+	notificationTimer -= rl.GetFrameTime()
+	rect := uiRect(AnchorTopLeft, 0, 15, 0, 20)
+	rect.Width, _ = renderer.ScreenSize()
+	drawUITextCentered(notification, rect, 0, 20, rl.Black)
+}
+
+// uiClicked reports whether rect was clicked this frame.
+func uiClicked(rect rl.Rectangle) bool {
+	return rl.IsMouseButtonPressed(rl.MouseLeftButton) && rl.CheckCollisionPointRec(rl.GetMousePosition(), rect)
+}
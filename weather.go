@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Weather kinds.
+const (
+	WeatherClear = iota
+	WeatherRain
+	WeatherSnow
+	WeatherFog
+	WeatherSandstorm
+)
+
+// Weather regions are square blocks of chunks that share the same weather.
+const WEATHER_REGION_CHUNKS = 4
+
+// Seconds of play between weather changes in a region.
+const WEATHER_PERIOD float32 = 90
+
+// Number of precipitation particles at full intensity.
+const WEATHER_PARTICLES = 600
+
+// weatherDef describes how a kind of weather looks and drives.
+type weatherDef struct {
+	Name       string
+	Grip       float32 // multiplier on acceleration and steering at full intensity
+	FogDensity float32 // exponential fog density at full intensity
+	FogColor   rl.Color
+}
+
+var weatherDefs = []weatherDef{
+	WeatherClear:     {Name: "Clear", Grip: 1, FogDensity: 0},
+	WeatherRain:      {Name: "Rain", Grip: 0.8, FogDensity: 0.01, FogColor: rl.NewColor(120, 130, 140, 255)},
+	WeatherSnow:      {Name: "Blizzard", Grip: 0.6, FogDensity: 0.03, FogColor: rl.NewColor(220, 225, 235, 255)},
+	WeatherFog:       {Name: "Fog", Grip: 0.95, FogDensity: 0.045, FogColor: rl.NewColor(180, 180, 185, 255)},
+	WeatherSandstorm: {Name: "Sandstorm", Grip: 0.85, FogDensity: 0.04, FogColor: rl.NewColor(200, 170, 110, 255)},
+}
+
+// weatherOdds lists, per chunk type, the cumulative chance of each weather kind;
+// anything left over is clear. Sandstorms only occur in Desert, blizzards in Snow.
+var weatherOdds = map[int][]struct {
+	Kind   int
+	Chance float32
+}{
+	Highway:    {{WeatherRain, 0.2}, {WeatherFog, 0.3}},
+	City:       {{WeatherRain, 0.25}, {WeatherFog, 0.35}},
+	Commercial: {{WeatherRain, 0.25}, {WeatherFog, 0.35}},
+	Desert:     {{WeatherSandstorm, 0.3}, {WeatherFog, 0.35}},
+	Forest:     {{WeatherRain, 0.3}, {WeatherFog, 0.5}},
+	Snow:       {{WeatherSnow, 0.4}, {WeatherFog, 0.5}},
+}
+
+// weatherParticle is one raindrop, snowflake or grain of sand.
+type weatherParticle struct {
+	Position rl.Vector3
+	Velocity rl.Vector3
+}
+
+var (
+	// Weather around the car and how strongly it is showing (0 to 1).
+	currentWeather   = WeatherClear
+	weatherIntensity float32
+	weatherParticles []weatherParticle
+)
+
+// weatherAt returns the weather for a chunk at the current play time.
+func weatherAt(coord Coord, chunkType int) int {
+	region := Coord{
+		int(math.Floor(float64(coord.X) / WEATHER_REGION_CHUNKS)),
+		int(math.Floor(float64(coord.Y) / WEATHER_REGION_CHUNKS)),
+	}
+	epoch := int(playTime / WEATHER_PERIOD)
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("weather:%d:%d,%d:%d", worldSeed, region.X, region.Y, epoch)))
+	roll := rand.New(rand.NewSource(int64(h.Sum64()))).Float32()
+	for _, odds := range weatherOdds[chunkType] {
+		if roll < odds.Chance {
+			return odds.Kind
+		}
+	}
+	return WeatherClear
+}
+
+// updateWeather eases between the weather of the regions the car drives through.
+func updateWeather() {
+	dt := rl.GetFrameTime()
+	target := WeatherClear
+	if chunk := chunks[getChunkCoord(car.position)]; chunk != nil {
+		target = weatherAt(chunk.Coord, chunk.Type)
+	}
+	// Fade the old weather out completely before the new one fades in.
+	if target != currentWeather {
+		weatherIntensity -= dt / 5
+		if weatherIntensity <= 0 {
+			weatherIntensity = 0
+			currentWeather = target
+		}
+	} else if currentWeather != WeatherClear {
+		weatherIntensity = float32(math.Min(1, float64(weatherIntensity+dt/5)))
+	}
+	updateWeatherParticles(dt)
+}
+
+// weatherGrip returns the grip multiplier applied to the car.
+func weatherGrip() float32 {
+	return 1 - (1-weatherDefs[currentWeather].Grip)*weatherIntensity
+}
+
+// weatherFog returns the fog density and color of the current weather.
+func weatherFog() (float32, rl.Color) {
+	def := weatherDefs[currentWeather]
+	return def.FogDensity * weatherIntensity, def.FogColor
+}
+
+// fogColor returns the color things fade into: the horizon, turning into the
+// weather's fog color (darkened at night) as the weather thickens.
+func fogColor() rl.Color {
+	density, color := weatherFog()
+	color = rl.ColorBrightness(color, -0.8*(1-daylight()))
+	return rl.ColorLerp(horizonColor(), color, rl.Clamp(density*40, 0, 1))
+}
+
+// applyFog uploads the fog settings and camera position to the shader.
+// Distance fog always closes in at VIEW_RADIUS; weather adds dense fog on top.
+func applyFog() {
+	if !instancingSupported {
+		return
+	}
+	density, _ := weatherFog()
+	color := rl.ColorNormalize(fogColor())
+	rl.SetShaderValue(instancingShader, viewPosLoc, []float32{camera.Position.X, camera.Position.Y, camera.Position.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(instancingShader, fogColorLoc, []float32{color.X, color.Y, color.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(instancingShader, fogDensityLoc, []float32{density}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(instancingShader, fogStartLoc, []float32{VIEW_RADIUS * FOG_START_RATIO}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(instancingShader, fogEndLoc, []float32{VIEW_RADIUS}, rl.ShaderUniformFloat)
+}
+
+// clearColor matches the fog so the background blends in where the sky dome is missing.
+func clearColor() rl.Color {
+	return fogColor()
+}
+
+// weatherLabel describes the conditions for the HUD.
+func weatherLabel() string {
+	if currentWeather == WeatherClear || weatherIntensity < 0.2 {
+		return "Weather: Clear"
+	}
+	return "Weather: " + weatherDefs[currentWeather].Name
+}
+
+// respawnWeatherParticle places a particle somewhere in the box around the camera.
+func respawnWeatherParticle(p *weatherParticle, anywhere bool) {
+	center := camera.Position
+	p.Position = rl.Vector3{
+		X: center.X + (rand.Float32()-0.5)*40,
+		Y: center.Y + 15,
+		Z: center.Z + (rand.Float32()-0.5)*40,
+	}
+	if anywhere {
+		p.Position.Y = center.Y + (rand.Float32()-0.5)*30
+	}
+	switch currentWeather {
+	case WeatherRain:
+		p.Velocity = rl.Vector3{X: 1, Y: -25, Z: 0.5}
+	case WeatherSnow:
+		p.Velocity = rl.Vector3{X: 4 + rand.Float32()*3, Y: -3 - rand.Float32()*2, Z: rand.Float32() * 2}
+	case WeatherSandstorm:
+		p.Velocity = rl.Vector3{X: 18 + rand.Float32()*6, Y: -0.5 + rand.Float32(), Z: rand.Float32()*4 - 2}
+		p.Position.Y = center.Y - 2 + rand.Float32()*6
+	}
+}
+
+// updateWeatherParticles moves precipitation and recycles particles that leave the view box.
+func updateWeatherParticles(dt float32) {
+	switch currentWeather {
+	case WeatherRain, WeatherSnow, WeatherSandstorm:
+	default:
+		weatherParticles = weatherParticles[:0]
+		return
+	}
+	want := int(WEATHER_PARTICLES * weatherIntensity)
+	for len(weatherParticles) < want {
+		var p weatherParticle
+		respawnWeatherParticle(&p, true)
+		weatherParticles = append(weatherParticles, p)
+	}
+	weatherParticles = weatherParticles[:want]
+	center := camera.Position
+	for i := range weatherParticles {
+		p := &weatherParticles[i]
+		p.Position = rl.Vector3Add(p.Position, rl.Vector3Scale(p.Velocity, dt))
+		if p.Position.Y < 0 || float32(math.Abs(float64(p.Position.X-center.X))) > 20 ||
+			float32(math.Abs(float64(p.Position.Z-center.Z))) > 20 {
+			respawnWeatherParticle(p, false)
+		}
+	}
+}
+
+// drawWeather draws the precipitation particles.
+func drawWeather() {
+	for _, p := range weatherParticles {
+		switch currentWeather {
+		case WeatherRain:
+			tail := rl.Vector3Add(p.Position, rl.Vector3Scale(p.Velocity, -0.03))
+			renderer.DrawLine3D(p.Position, tail, rl.Fade(rl.SkyBlue, 0.6))
+		case WeatherSnow:
+			renderer.DrawCube(p.Position, rl.Vector3{X: 0.08, Y: 0.08, Z: 0.08}, rl.White)
+		case WeatherSandstorm:
+			renderer.DrawCube(p.Position, rl.Vector3{X: 0.06, Y: 0.06, Z: 0.06}, rl.NewColor(210, 180, 120, 255))
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"hash/fnv"
 	"math"
 	"math/rand"
+	"slices"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -12,6 +13,9 @@ import (
 // Use float32 for CHUNK_SIZE.
 const CHUNK_SIZE float32 = 50.0
 
+// Width of every road strip.
+const ROAD_WIDTH float32 = 5.0
+
 var LightBlue = rl.Color{R: 173, G: 216, B: 230, A: 255} // RGB for light blue
 
 // Chunk types.
@@ -36,17 +40,40 @@ type Coord struct {
 	X, Y int
 }
 
-// Chunk holds the chunk type, road type, and models for rendering.
+// Chunks further than this (in chunks, on either axis) from the car are unloaded.
+const CHUNK_UNLOAD_RADIUS = 3
+
+// Chunk holds the chunk type, road type, props and their colliders.
 type Chunk struct {
-	Type     int
-	RoadType int
-	Coord    Coord
-	Models   []rl.Model
+	Type      int
+	RoadType  int
+	Coord     Coord
+	Props     []propInstance
+	Markers   []mapMarker
+	Colliders []rl.BoundingBox
+	// Landmark built into the chunk, if any, and where it is used or shown on the maps.
+	Landmark     int
+	LandmarkSpot rl.Vector3
+	Pieces       []landmarkPiece
+	// Ground taken by the landmark, kept free of props.
+	Reserved []rl.BoundingBox
+	// Seeded pickups, job and speed camera, placed once when the chunk is built.
+	Collectibles []collectible
+	Offer        *mission
+	SpeedTrap    *rl.Vector3
+}
+
+// roadStrip is an axis-aligned road rectangle in world space.
+type roadStrip struct {
+	X, Z          float32 // centre
+	Width, Length float32 // extent along X and Z
 }
 
 var (
 	chunks          map[Coord]*Chunk
 	lastPlayerChunk Coord
+	// Seed for all procedural generation; stored in save games.
+	worldSeed int64
 	// Allowed neighbors for each chunk type.
 	allowedNeighbors = [][]int{
 		{Highway, City, Commercial, Desert, Forest, Snow}, // Highway
@@ -65,11 +92,16 @@ var (
 		rl.Green,    // Forest
 		rl.White,    // Snow
 	}
+	// Display names for chunk types.
+	typeNames = []string{"Highway", "City", "Commercial", "Desert", "Forest", "Snow"}
+	// Colors for roads based on road type.
+	roadColors = []rl.Color{
+		rl.DarkGray,                   // RoadNormal
+		rl.NewColor(139, 69, 19, 255), // RoadDirt (brownish)
+		LightBlue,                     // RoadIce
+	}
 )
 
-// collisionBoxes holds bounding boxes for objects.
-var collisionBoxes []rl.BoundingBox
-
 // getChunkCoord converts a world position to chunk coordinates.
 func getChunkCoord(pos rl.Vector3) Coord {
 	i := int(math.Floor(float64(pos.X / CHUNK_SIZE)))
@@ -77,10 +109,39 @@ func getChunkCoord(pos rl.Vector3) Coord {
 	return Coord{i, j}
 }
 
-// checkCollisions returns true if the given position collides with any bounding box.
-func checkCollisions(pos rl.Vector3) bool {
+// nearbyColliders returns the colliders of the loaded chunks within radius chunks of pos.
+func nearbyColliders(pos rl.Vector3, radius int) []rl.BoundingBox {
+	var boxes []rl.BoundingBox
+	center := getChunkCoord(pos)
+	for i := center.X - radius; i <= center.X+radius; i++ {
+		for j := center.Y - radius; j <= center.Y+radius; j++ {
+			if chunk := chunks[Coord{i, j}]; chunk != nil {
+				boxes = append(boxes, chunk.Colliders...)
+			}
+		}
+	}
+	return boxes
+}
+
+// removeCollider takes a box out of a chunk's colliders.
+func removeCollider(chunk *Chunk, box rl.BoundingBox) {
+	kept := chunk.Colliders[:0]
+	for _, b := range chunk.Colliders {
+		if b != box {
+			kept = append(kept, b)
+		}
+	}
+	chunk.Colliders = kept
+}
+
+// checkCollisions returns true if the car moving from one position to pos
+// collides with a prop, a traffic vehicle or the police.
+func checkCollisions(from, pos rl.Vector3) bool {
+	if trafficCollision(from, pos) || policeNear(pos, 2) {
+		return true
+	}
 	// Treat the car as a circle with radius 1 (XZ plane).
-	for _, box := range collisionBoxes {
+	for _, box := range nearbyColliders(pos, 1) {
 		centerX := (box.Min.X + box.Max.X) / 2
 		centerZ := (box.Min.Z + box.Max.Z) / 2
 		dx := pos.X - centerX
@@ -94,57 +155,110 @@ func checkCollisions(pos rl.Vector3) bool {
 	return false
 }
 
-// setAlbedoColor updates the model's material color.
-func setAlbedoColor(model *rl.Model, color rl.Color) {
-	mat := *model.Materials // copy the material
-	mat.Maps.Color = color
-	model.Materials = &mat
-}
-
 // isPositionOnRoad returns true if (x,z) (relative to the chunk origin) lies on the road.
 // In our design the road is a plus shape through the center.
 func isPositionOnRoad(x, z float32) bool {
-	roadWidth := float32(5.0)
 	center := CHUNK_SIZE / 2
-	if math.Abs(float64(x-center)) <= float64(roadWidth) || math.Abs(float64(z-center)) <= float64(roadWidth) {
+	if math.Abs(float64(x-center)) <= float64(ROAD_WIDTH) || math.Abs(float64(z-center)) <= float64(ROAD_WIDTH) {
 		return true
 	}
 	return false
 }
 
+// chunkRoads returns the road strips of a chunk: the main "+" road through the
+// centre and, for City/Commercial chunks away from the origin, a ring of backroads.
+func chunkRoads(chunk *Chunk) []roadStrip {
+	posX := float32(chunk.Coord.X) * CHUNK_SIZE
+	posZ := float32(chunk.Coord.Y) * CHUNK_SIZE
+	roads := []roadStrip{
+		{X: posX + CHUNK_SIZE/2, Z: posZ + CHUNK_SIZE/2, Width: CHUNK_SIZE, Length: ROAD_WIDTH},
+		{X: posX + CHUNK_SIZE/2, Z: posZ + CHUNK_SIZE/2, Width: ROAD_WIDTH, Length: CHUNK_SIZE},
+	}
+	if chunk.Coord == (Coord{0, 0}) {
+		return roads
+	}
+	if chunk.Type == City || chunk.Type == Commercial {
+		roads = append(roads,
+			roadStrip{X: posX + CHUNK_SIZE/2, Z: posZ + CHUNK_SIZE - ROAD_WIDTH, Width: CHUNK_SIZE, Length: ROAD_WIDTH},
+			roadStrip{X: posX + CHUNK_SIZE/2, Z: posZ + ROAD_WIDTH, Width: CHUNK_SIZE, Length: ROAD_WIDTH},
+			roadStrip{X: posX + ROAD_WIDTH, Z: posZ + CHUNK_SIZE/2, Width: ROAD_WIDTH, Length: CHUNK_SIZE},
+			roadStrip{X: posX + CHUNK_SIZE - ROAD_WIDTH, Z: posZ + CHUNK_SIZE/2, Width: ROAD_WIDTH, Length: CHUNK_SIZE},
+		)
+	}
+	return roads
+}
+
+// chunkRandom returns a random source that is stable for chunk (i,j) within the world seed.
+func chunkRandom(i, j int) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%d:%d,%d", worldSeed, i, j)))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// seededRandom returns a random source for chunk (i,j) that is stable within
+// the world seed and independent of chunkRandom and other purposes.
+func seededRandom(purpose string, i, j int) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%s:%d:%d,%d", purpose, worldSeed, i, j)))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
 // determineChunkType returns a chunk type based on neighbors.
-func determineChunkType(i, j int) int {
+func determineChunkType(i, j int, r *rand.Rand) int {
 	neighbors := []Coord{{i - 1, j}, {i + 1, j}, {i, j - 1}, {i, j + 1}}
-	var types []int
+	// A neighbor holding a landmark also limits its neighbors to the
+	// landmark's types; its rules win when not every neighbor can be satisfied.
+	var rules, landmarkRules [][]int
 	for _, n := range neighbors {
-		if chunk, exists := chunks[n]; exists {
-			types = append(types, chunk.Type)
+		seen, ok := knownChunk(n)
+		if !ok {
+			continue
 		}
-	}
-	if len(types) == 0 {
-		return rand.Intn(6)
-	}
-	allowed := make(map[int]bool)
-	for _, t := range allowedNeighbors[types[0]] {
-		allowed[t] = true
-	}
-	for _, t := range types[1:] {
-		newAllowed := make(map[int]bool)
-		for _, a := range allowedNeighbors[t] {
-			if allowed[a] {
-				newAllowed[a] = true
-			}
+		if seen.Landmark != LandmarkNone {
+			landmarkRules = append(landmarkRules, allowedNeighbors[seen.Type], landmarkDefs[seen.Landmark].Neighbors)
+		} else {
+			rules = append(rules, allowedNeighbors[seen.Type])
 		}
-		allowed = newAllowed
 	}
-	var allowedTypes []int
-	for t := range allowed {
-		allowedTypes = append(allowedTypes, t)
+	if len(rules) == 0 && len(landmarkRules) == 0 {
+		return r.Intn(6)
+	}
+	allowedTypes := allowedByAll(append(rules, landmarkRules...))
+	if len(allowedTypes) == 0 {
+		allowedTypes = allowedByAll(landmarkRules)
 	}
 	if len(allowedTypes) == 0 {
 		return Highway
 	}
-	return allowedTypes[rand.Intn(len(allowedTypes))]
+	return allowedTypes[r.Intn(len(allowedTypes))]
+}
+
+// allowedByAll returns the chunk types, in order, that every list allows; none for no lists.
+func allowedByAll(lists [][]int) []int {
+	if len(lists) == 0 {
+		return nil
+	}
+	var types []int
+	for t := range allowedNeighbors {
+		ok := true
+		for _, list := range lists {
+			ok = ok && slices.Contains(list, t)
+		}
+		if ok {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// knownChunk returns what was generated at coord, whether the chunk is loaded
+// or unloaded and remembered by the world map.
+func knownChunk(coord Coord) (discoveredChunk, bool) {
+	if chunk, exists := chunks[coord]; exists {
+		return discoveredChunk{Type: chunk.Type, RoadType: chunk.RoadType, Landmark: chunk.Landmark}, true
+	}
+	seen, ok := discovered[coord]
+	return seen, ok
 }
 
 // generateChunk creates a chunk at grid coordinate (i,j) with ground, road, and objects.
@@ -153,7 +267,13 @@ func generateChunk(i, j int) {
 	if _, exists := chunks[coord]; exists {
 		return
 	}
-	chunkType := determineChunkType(i, j)
+	// Previously discovered chunks keep their type so the world matches the map.
+	if seen, ok := discovered[coord]; ok {
+		buildChunk(coord, seen.Type, seen.RoadType, seen.Landmark)
+		return
+	}
+	r := chunkRandom(i, j)
+	chunkType := determineChunkType(i, j, r)
 	// Determine road type based on chunk type.
 	var roadType int
 	switch chunkType {
@@ -162,7 +282,7 @@ func generateChunk(i, j int) {
 	case Snow:
 		roadType = RoadIce
 	case Highway:
-		if rand.Float32() < 0.3 {
+		if r.Float32() < 0.3 {
 			roadType = RoadDirt
 		} else {
 			roadType = RoadNormal
@@ -170,179 +290,96 @@ func generateChunk(i, j int) {
 	default:
 		roadType = RoadNormal
 	}
-	chunk := &Chunk{Type: chunkType, RoadType: roadType, Coord: coord, Models: []rl.Model{}}
-	posX := float32(i) * CHUNK_SIZE
-	posZ := float32(j) * CHUNK_SIZE
+	buildChunk(coord, chunkType, roadType, chooseLandmark(i, j, chunkType))
+}
 
-	// Ground.
-	groundMesh := rl.GenMeshPlane(CHUNK_SIZE, CHUNK_SIZE, 1, 1)
-	groundModel := rl.LoadModelFromMesh(groundMesh)
-	groundTransform := rl.MatrixTranslate(posX+CHUNK_SIZE/2, 0, posZ+CHUNK_SIZE/2)
-	groundModel.Transform = groundTransform
-	setAlbedoColor(&groundModel, typeColors[chunkType])
-	chunk.Models = append(chunk.Models, groundModel)
-
-	// Choose road color based on road type.
-	var roadColor rl.Color
-	switch roadType {
-	case RoadNormal:
-		roadColor = rl.DarkGray
-	case RoadDirt:
-		roadColor = rl.NewColor(139, 69, 19, 255) // brownish
-	case RoadIce:
-		roadColor = LightBlue
-	}
-
-	// Main "+" road.
-	roadWidth := float32(5.0)
-	roadMeshH := rl.GenMeshPlane(CHUNK_SIZE, roadWidth, 1, 1)
-	roadModelH := rl.LoadModelFromMesh(roadMeshH)
-	roadTransformH := rl.MatrixTranslate(posX+CHUNK_SIZE/2, 0.01, posZ+CHUNK_SIZE/2)
-	roadModelH.Transform = roadTransformH
-	setAlbedoColor(&roadModelH, roadColor)
-	chunk.Models = append(chunk.Models, roadModelH)
-
-	roadMeshV := rl.GenMeshPlane(roadWidth, CHUNK_SIZE, 1, 1)
-	roadModelV := rl.LoadModelFromMesh(roadMeshV)
-	roadTransformV := rl.MatrixTranslate(posX+CHUNK_SIZE/2, 0.01, posZ+CHUNK_SIZE/2)
-	roadModelV.Transform = roadTransformV
-	setAlbedoColor(&roadModelV, roadColor)
-	chunk.Models = append(chunk.Models, roadModelV)
+// buildChunk creates a chunk of the given type with its props, seeded
+// content and vehicles.
+func buildChunk(coord Coord, chunkType, roadType, landmark int) {
+	chunk := &Chunk{Type: chunkType, RoadType: roadType, Coord: coord, Landmark: landmark}
+	chunks[coord] = chunk
+	buildChunkProps(chunk)
 
-	// For central chunk (0,0), only ground and main road.
-	if i == 0 && j == 0 {
-		chunks[coord] = chunk
-		return
+	// Placed around the props, before roadblocks or knocked props change the colliders.
+	chunk.Collectibles = chunkCollectibles(chunk)
+	if m, ok := chunkMissionOffer(chunk); ok {
+		chunk.Offer = &m
+	}
+	if trap, ok := chunkSpeedTrap(chunk); ok {
+		chunk.SpeedTrap = &trap
 	}
 
-	// For City/Commercial chunks, add backroads.
-	if chunkType == City || chunkType == Commercial {
-		roadModelTop := rl.LoadModelFromMesh(roadMeshH)
-		roadTopTransform := rl.MatrixTranslate(posX+CHUNK_SIZE/2, 0.01, posZ+CHUNK_SIZE-roadWidth)
-		roadModelTop.Transform = roadTopTransform
-		setAlbedoColor(&roadModelTop, roadColor)
-		chunk.Models = append(chunk.Models, roadModelTop)
+	spawnChunkTraffic(chunk)
+	spawnChunkPedestrians(chunk)
+	spawnChunkRoadblock(chunk)
+}
 
-		roadModelBottom := rl.LoadModelFromMesh(roadMeshH)
-		roadBottomTransform := rl.MatrixTranslate(posX+CHUNK_SIZE/2, 0.01, posZ+roadWidth)
-		roadModelBottom.Transform = roadBottomTransform
-		setAlbedoColor(&roadModelBottom, roadColor)
-		chunk.Models = append(chunk.Models, roadModelBottom)
+// buildChunkProps creates the props, markers and colliders of a chunk.
+func buildChunkProps(chunk *Chunk) {
+	i, j := chunk.Coord.X, chunk.Coord.Y
+	chunkType, coord, landmark := chunk.Type, chunk.Coord, chunk.Landmark
 
-		roadModelLeft := rl.LoadModelFromMesh(roadMeshV)
-		roadLeftTransform := rl.MatrixTranslate(posX+roadWidth, 0.01, posZ+CHUNK_SIZE/2)
-		roadModelLeft.Transform = roadLeftTransform
-		setAlbedoColor(&roadModelLeft, roadColor)
-		chunk.Models = append(chunk.Models, roadModelLeft)
+	// For central chunk (0,0), only ground and main road.
+	if i == 0 && j == 0 {
+		return
+	}
 
-		roadModelRight := rl.LoadModelFromMesh(roadMeshV)
-		roadRightTransform := rl.MatrixTranslate(posX+CHUNK_SIZE-roadWidth, 0.01, posZ+CHUNK_SIZE/2)
-		roadModelRight.Transform = roadRightTransform
-		setAlbedoColor(&roadModelRight, roadColor)
-		chunk.Models = append(chunk.Models, roadModelRight)
+	posX := float32(i) * CHUNK_SIZE
+	posZ := float32(j) * CHUNK_SIZE
+	// Streetlights along the main roads of City chunks.
+	if chunkType == City {
+		for _, offset := range streetlightOffsets {
+			prop := newPropInstance(PropStreetlight, posX+CHUNK_SIZE/2+offset.X, posZ+CHUNK_SIZE/2+offset.Y)
+			chunk.Props = append(chunk.Props, prop)
+			chunk.Colliders = append(chunk.Colliders, prop.bounds())
+		}
 	}
 
-	// Seeded randomness for object placement.
-	h := fnv.New64a()
-	h.Write([]byte(fmt.Sprintf("%d,%d", i, j)))
-	seed := h.Sum64()
-	chunkRand := rand.New(rand.NewSource(int64(seed)))
+	if landmark != LandmarkNone {
+		buildLandmark(chunk)
+	}
 
-	// Spawn objects based on chunk type.
-	switch chunkType {
-	case City:
-		for k := 0; k < 5; k++ {
-			bx := posX + chunkRand.Float32()*CHUNK_SIZE
-			bz := posZ + chunkRand.Float32()*CHUNK_SIZE
-			if !isPositionOnRoad(bx-posX, bz-posZ) {
-				buildingMesh := rl.GenMeshCube(10, 50, 10)
-				buildingModel := rl.LoadModelFromMesh(buildingMesh)
-				buildingTransform := rl.MatrixTranslate(bx, 25, bz)
-				buildingModel.Transform = buildingTransform
-				setAlbedoColor(&buildingModel, rl.Blue)
-				chunk.Models = append(chunk.Models, buildingModel)
-				box := rl.BoundingBox{
-					Min: rl.Vector3{X: bx - 5, Y: 0, Z: bz - 5},
-					Max: rl.Vector3{X: bx + 5, Y: 50, Z: bz + 5},
-				}
-				collisionBoxes = append(collisionBoxes, box)
+	// Signs and fences beside the main roads, clear of the intersection.
+	if roadside, ok := roadsideProps[chunkType]; ok {
+		r := seededRandom("roadside", i, j)
+		center := chunkCenter(coord)
+		for k := 0; k < roadside.Count; k++ {
+			dir := r.Intn(4)
+			if roadside.Type == PropFence {
+				dir = 2 * r.Intn(2)
 			}
-		}
-	case Commercial:
-		for k := 0; k < 3; k++ {
-			sx := posX + chunkRand.Float32()*CHUNK_SIZE
-			sz := posZ + chunkRand.Float32()*CHUNK_SIZE
-			if !isPositionOnRoad(sx-posX, sz-posZ) {
-				storeMesh := rl.GenMeshCube(15, 10, 15)
-				storeModel := rl.LoadModelFromMesh(storeMesh)
-				storeTransform := rl.MatrixTranslate(sx, 5, sz)
-				storeModel.Transform = storeTransform
-				setAlbedoColor(&storeModel, rl.Purple)
-				chunk.Models = append(chunk.Models, storeModel)
-				box := rl.BoundingBox{
-					Min: rl.Vector3{X: sx - 7.5, Y: 0, Z: sz - 7.5},
-					Max: rl.Vector3{X: sx + 7.5, Y: 10, Z: sz + 7.5},
-				}
-				collisionBoxes = append(collisionBoxes, box)
+			side := float32(2*r.Intn(2) - 1)
+			at := rl.Vector3Add(center, rl.Vector3Scale(trafficDirs[dir], 8+r.Float32()*14))
+			at = rl.Vector3Add(at, rl.Vector3Scale(trafficDirs[(dir+1)%4], side*(ROAD_WIDTH/2+2.5)))
+			if isReserved(chunk, at.X, at.Z) {
+				continue
 			}
+			prop := newPropInstance(roadside.Type, at.X, at.Z)
+			chunk.Props = append(chunk.Props, prop)
+			chunk.Colliders = append(chunk.Colliders, prop.bounds())
 		}
-	case Desert:
-		for k := 0; k < 10; k++ {
-			cx := posX + chunkRand.Float32()*CHUNK_SIZE
-			cz := posZ + chunkRand.Float32()*CHUNK_SIZE
-			if !isPositionOnRoad(cx-posX, cz-posZ) {
-				cactusMesh := rl.GenMeshCube(1, 5, 1)
-				cactusModel := rl.LoadModelFromMesh(cactusMesh)
-				cactusTransform := rl.MatrixTranslate(cx, 2.5, cz)
-				cactusModel.Transform = cactusTransform
-				setAlbedoColor(&cactusModel, rl.Green)
-				chunk.Models = append(chunk.Models, cactusModel)
-				box := rl.BoundingBox{
-					Min: rl.Vector3{X: cx - 0.5, Y: 0, Z: cz - 0.5},
-					Max: rl.Vector3{X: cx + 0.5, Y: 5, Z: cz + 0.5},
-				}
-				collisionBoxes = append(collisionBoxes, box)
-			}
-		}
-	case Forest:
-		for k := 0; k < 20; k++ {
-			tx := posX + chunkRand.Float32()*CHUNK_SIZE
-			tz := posZ + chunkRand.Float32()*CHUNK_SIZE
-			if !isPositionOnRoad(tx-posX, tz-posZ) {
-				treeMesh := rl.GenMeshCube(2, 10, 2)
-				treeModel := rl.LoadModelFromMesh(treeMesh)
-				treeTransform := rl.MatrixTranslate(tx, 5, tz)
-				treeModel.Transform = treeTransform
-				setAlbedoColor(&treeModel, rl.DarkGreen)
-				chunk.Models = append(chunk.Models, treeModel)
-				box := rl.BoundingBox{
-					Min: rl.Vector3{X: tx - 1, Y: 0, Z: tz - 1},
-					Max: rl.Vector3{X: tx + 1, Y: 10, Z: tz + 1},
-				}
-				collisionBoxes = append(collisionBoxes, box)
-			}
+	}
+
+	spawn, ok := chunkProps[chunkType]
+	if !ok {
+		return
+	}
+	// Seeded randomness for object placement.
+	chunkRand := chunkRandom(i, j)
+	def := propDefs[spawn.Type]
+	for k := 0; k < spawn.Count; k++ {
+		x := posX + chunkRand.Float32()*CHUNK_SIZE
+		z := posZ + chunkRand.Float32()*CHUNK_SIZE
+		if isPositionOnRoad(x-posX, z-posZ) || isReserved(chunk, x, z) {
+			continue
 		}
-	case Snow:
-		for k := 0; k < 2; k++ {
-			ix := posX + chunkRand.Float32()*CHUNK_SIZE
-			iz := posZ + chunkRand.Float32()*CHUNK_SIZE
-			if !isPositionOnRoad(ix-posX, iz-posZ) {
-				iglooMesh := rl.GenMeshSphere(5, 16, 16)
-				iglooModel := rl.LoadModelFromMesh(iglooMesh)
-				iglooTransform := rl.MatrixTranslate(ix, 5, iz)
-				iglooModel.Transform = iglooTransform
-				setAlbedoColor(&iglooModel, rl.White)
-				chunk.Models = append(chunk.Models, iglooModel)
-				box := rl.BoundingBox{
-					Min: rl.Vector3{X: ix - 5, Y: 0, Z: iz - 5},
-					Max: rl.Vector3{X: ix + 5, Y: 10, Z: iz + 5},
-				}
-				collisionBoxes = append(collisionBoxes, box)
-			}
+		prop := newPropInstance(spawn.Type, x, z)
+		chunk.Props = append(chunk.Props, prop)
+		chunk.Colliders = append(chunk.Colliders, prop.bounds())
+		if def.Marker {
+			chunk.Markers = append(chunk.Markers, mapMarker{Position: prop.Position, Color: def.Color})
 		}
 	}
-
-	chunks[coord] = chunk
 }
 
 // updateWorld generates new chunks as the player moves.
@@ -370,12 +407,41 @@ func updateWorld() {
 			}
 		}
 		lastPlayerChunk = playerChunk
+		unloadDistantChunks(playerChunk)
+	}
+}
+
+// unloadDistantChunks drops chunks that have fallen far behind the car along
+// with everything living in them. Discovered chunks come back with the same
+// type when regenerated, and props are seeded, so nothing visible changes.
+func unloadDistantChunks(center Coord) {
+	for coord := range chunks {
+		if abs(coord.X-center.X) > CHUNK_UNLOAD_RADIUS || abs(coord.Y-center.Y) > CHUNK_UNLOAD_RADIUS {
+			despawnChunkTraffic(coord)
+			despawnChunkPedestrians(coord)
+			despawnChunkRoadblocks(coord)
+			delete(chunks, coord)
+		}
+	}
+}
+
+// abs returns the absolute value of an int.
+func abs(v int) int {
+	if v < 0 {
+		return -v
 	}
+	return v
 }
 
-// drawWorld renders all chunks in the visible 5x5 grid.
+// drawWorld renders all chunks in the visible 5x5 grid, batching identical meshes.
 func drawWorld() {
 	updateWorld()
+	groundBatches := make([][]rl.Matrix, len(groundMaterials))
+	roadBatchesH := make([][]rl.Matrix, len(roadMaterials))
+	roadBatchesV := make([][]rl.Matrix, len(roadMaterials))
+	propBatches := make([][]rl.Matrix, len(propDefs))
+	var sidewalksH, sidewalksV []rl.Matrix
+
 	playerChunk := getChunkCoord(car.position)
 	for i := playerChunk.X - 2; i <= playerChunk.X+2; i++ {
 		for j := playerChunk.Y - 2; j <= playerChunk.Y+2; j++ {
@@ -384,20 +450,53 @@ func drawWorld() {
 				generateChunk(i, j)
 			}
 			chunk := chunks[coord]
-			for _, model := range chunk.Models {
-				rl.DrawModel(model, rl.Vector3{}, 1, rl.White)
+			groundTransform := rl.MatrixTranslate(float32(i)*CHUNK_SIZE+CHUNK_SIZE/2, 0, float32(j)*CHUNK_SIZE+CHUNK_SIZE/2)
+			groundBatches[chunk.Type] = append(groundBatches[chunk.Type], groundTransform)
+			for _, road := range chunkRoads(chunk) {
+				roadTransform := rl.MatrixTranslate(road.X, 0.01, road.Z)
+				if road.Width == CHUNK_SIZE {
+					roadBatchesH[chunk.RoadType] = append(roadBatchesH[chunk.RoadType], roadTransform)
+				} else {
+					roadBatchesV[chunk.RoadType] = append(roadBatchesV[chunk.RoadType], roadTransform)
+				}
+			}
+			for _, walk := range chunkSidewalks(chunk) {
+				walkTransform := rl.MatrixTranslate(walk.X, 0.02, walk.Z)
+				if walk.Width == CHUNK_SIZE {
+					sidewalksH = append(sidewalksH, walkTransform)
+				} else {
+					sidewalksV = append(sidewalksV, walkTransform)
+				}
+			}
+			for _, prop := range chunk.Props {
+				propBatches[prop.Type] = append(propBatches[prop.Type], prop.Transform)
 			}
 		}
 	}
+
+	for t, transforms := range groundBatches {
+		renderer.DrawMeshes(groundMesh, groundMaterials[t], transforms)
+	}
+	for t := range roadMaterials {
+		renderer.DrawMeshes(roadMeshH, roadMaterials[t], roadBatchesH[t])
+		renderer.DrawMeshes(roadMeshV, roadMaterials[t], roadBatchesV[t])
+	}
+	renderer.DrawMeshes(sidewalkMeshH, sidewalkMaterial, sidewalksH)
+	renderer.DrawMeshes(sidewalkMeshV, sidewalkMaterial, sidewalksV)
+	for t, transforms := range propBatches {
+		renderer.DrawModel(propDefs[t].model, transforms, rl.White)
+	}
 }
 
-// initWorld initializes the world by generating a 5x5 grid and resetting collision boxes.
+// initWorld initializes the world by generating a 5x5 grid around the car and clearing traffic and pedestrians.
 func initWorld() {
 	chunks = make(map[Coord]*Chunk)
-	collisionBoxes = []rl.BoundingBox{}
-	lastPlayerChunk = Coord{0, 0}
-	for i := -2; i <= 2; i++ {
-		for j := -2; j <= 2; j++ {
+	trafficCars = nil
+	pedestrians = nil
+	roadblocks = nil
+	lastPlayerChunk = getChunkCoord(car.position)
+	for i := lastPlayerChunk.X - 2; i <= lastPlayerChunk.X+2; i++ {
+		for j := lastPlayerChunk.Y - 2; j <= lastPlayerChunk.Y+2; j++ {
 			generateChunk(i, j)
 		}
 	}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Chunks within this many chunks of the player are marked as discovered.
+const DISCOVERY_RADIUS = 2
+
+// discoveredChunk records what the player has seen of a chunk.
+type discoveredChunk struct {
+	Type     int
+	RoadType int
+	Landmark int
+}
+
+var (
+	// discovered holds every chunk the player has seen, across sessions.
+	discovered = map[Coord]discoveredChunk{}
+	// World XZ position shown at the center of the map screen.
+	worldMapCenter rl.Vector2
+	// Map zoom in pixels per meter.
+	worldMapScale float32 = 0.5
+)
+
+// updateDiscovery marks the chunks around the player as discovered.
+func updateDiscovery() {
+	playerChunk := getChunkCoord(car.position)
+	for i := playerChunk.X - DISCOVERY_RADIUS; i <= playerChunk.X+DISCOVERY_RADIUS; i++ {
+		for j := playerChunk.Y - DISCOVERY_RADIUS; j <= playerChunk.Y+DISCOVERY_RADIUS; j++ {
+			coord := Coord{i, j}
+			if chunk, exists := chunks[coord]; exists {
+				discovered[coord] = discoveredChunk{Type: chunk.Type, RoadType: chunk.RoadType, Landmark: chunk.Landmark}
+			}
+		}
+	}
+}
+
+// openWorldMap switches to the map screen centered on the car.
+func openWorldMap() {
+	worldMapCenter = rl.Vector2{X: car.position.X, Y: car.position.Z}
+	currentState = WorldMap
+}
+
+// updateWorldMap handles panning (drag or arrow keys) and zooming (mouse wheel).
+func updateWorldMap() {
+	if rl.IsKeyPressed(rl.KeyM) {
+		currentState = Playing
+		return
+	}
+
+	// Pan at a constant screen speed regardless of zoom.
+	pan := 400 / worldMapScale * rl.GetFrameTime()
+	if rl.IsKeyDown(rl.KeyLeft) {
+		worldMapCenter.X -= pan
+	}
+	if rl.IsKeyDown(rl.KeyRight) {
+		worldMapCenter.X += pan
+	}
+	if rl.IsKeyDown(rl.KeyUp) {
+		worldMapCenter.Y -= pan
+	}
+	if rl.IsKeyDown(rl.KeyDown) {
+		worldMapCenter.Y += pan
+	}
+	if rl.IsMouseButtonDown(rl.MouseLeftButton) {
+		delta := rl.GetMouseDelta()
+		worldMapCenter.X -= delta.X / worldMapScale
+		worldMapCenter.Y -= delta.Y / worldMapScale
+	}
+
+	if wheel := rl.GetMouseWheelMove(); wheel != 0 {
+		if wheel > 0 {
+			worldMapScale *= 1.25
+		} else {
+			worldMapScale /= 1.25
+		}
+		worldMapScale = rl.Clamp(worldMapScale, 0.05, 4)
+	}
+	if rl.IsKeyPressed(rl.KeyC) {
+		worldMapCenter = rl.Vector2{X: car.position.X, Y: car.position.Z}
+	}
+}
+
+// worldToMapScreen projects a world XZ position onto the full-screen map (north up).
+func worldToMapScreen(x, z float32) rl.Vector2 {
+	screenW, screenH := renderer.ScreenSize()
+	return rl.Vector2{
+		X: screenW/2 + (x-worldMapCenter.X)*worldMapScale,
+		Y: screenH/2 + (z-worldMapCenter.Y)*worldMapScale,
+	}
+}
+
+// drawWorldMap renders all discovered chunks; undiscovered space stays dark.
+func drawWorldMap() {
+	renderer.ClearBackground(rl.Black)
+	screenW, screenH := renderer.ScreenSize()
+	screen := rl.Rectangle{Width: screenW, Height: screenH}
+	size := CHUNK_SIZE * worldMapScale
+
+	for coord, seen := range discovered {
+		corner := worldToMapScreen(float32(coord.X)*CHUNK_SIZE, float32(coord.Y)*CHUNK_SIZE)
+		rect := rl.Rectangle{X: corner.X, Y: corner.Y, Width: size, Height: size}
+		if !rl.CheckCollisionRecs(rect, screen) {
+			continue
+		}
+		renderer.DrawRectangle(rect, typeColors[seen.Type])
+		for _, road := range chunkRoads(&Chunk{Type: seen.Type, RoadType: seen.RoadType, Coord: coord}) {
+			pos := worldToMapScreen(road.X-road.Width/2, road.Z-road.Length/2)
+			renderer.DrawRectangle(rl.Rectangle{X: pos.X, Y: pos.Y, Width: road.Width * worldMapScale, Height: road.Length * worldMapScale},
+				roadColors[seen.RoadType])
+		}
+	}
+
+	// Landmarks, named once zoomed in far enough to read.
+	for coord, seen := range discovered {
+		if seen.Landmark == LandmarkNone {
+			continue
+		}
+		def := landmarkDefs[seen.Landmark]
+		center := chunkCenter(coord)
+		pos := worldToMapScreen(center.X, center.Z)
+		renderer.DrawCircle(pos, ui(6), def.Color)
+		if worldMapScale >= 0.25 {
+			drawUIText(def.Name, pos.X+ui(8), pos.Y-ui(8), 16, rl.White)
+		}
+	}
+
+	drawCarArrow(worldToMapScreen(car.position.X, car.position.Z), car.yaw, ui(10), rl.Red)
+
+	// Legend and controls.
+	for i, name := range typeNames {
+		swatch := uiRect(AnchorTopLeft, 10, float32(10+25*i), 20, 20)
+		renderer.DrawRectangle(swatch, typeColors[i])
+		label := name
+		if t := collectTotals[i]; t.Coins > 0 || t.Tokens > 0 {
+			label += fmt.Sprintf("  %d coins, %d tokens", t.Coins, t.Tokens)
+		}
+		drawUIText(label, swatch.X+ui(30), swatch.Y, 20, rl.White)
+	}
+	for k, def := range landmarkDefs[1:] {
+		swatch := uiRect(AnchorTopLeft, 20, float32(10+25*(len(typeNames)+k)), 0, 0)
+		renderer.DrawCircle(rl.Vector2{X: swatch.X, Y: swatch.Y + ui(10)}, ui(6), def.Color)
+		drawUIText(def.Name, swatch.X+ui(20), swatch.Y, 20, rl.White)
+	}
+	status := uiRect(AnchorBottomLeft, 10, 35, 0, 20)
+	drawUIText(fmt.Sprintf("Discovered chunks: %d", len(discovered)), status.X, status.Y, 20, rl.White)
+	help := uiRect(AnchorBottomLeft, 10, 10, 0, 20)
+	drawUIText("Drag/arrows: pan  Wheel: zoom  C: center  M: close", help.X, help.Y, 20, rl.White)
+}